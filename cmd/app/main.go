@@ -2,7 +2,11 @@ package main
 
 import (
 	_ "blogpoint-backend/docs"
+	"blogpoint-backend/internal/config"
 	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/media"
+	"blogpoint-backend/internal/notify"
+	"blogpoint-backend/internal/realtime"
 	"blogpoint-backend/internal/repository"
 	"blogpoint-backend/internal/routes"
 	"blogpoint-backend/internal/storage"
@@ -11,6 +15,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
+	"log"
+	"strings"
 )
 
 // @title BlogPoint API
@@ -24,28 +30,51 @@ import (
 // @in cookie
 // @name jwt
 func main() {
-	repository.Connect()
-	storage.InitMinio()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	log.Printf("Loaded configuration: %s", cfg)
+
+	repository.Connect(cfg.Database)
+	repository.BackfillSlugs()
+	storage.InitMinio(cfg.Minio)
+	media.Configure(cfg.Media)
+
+	mail.Configure(cfg.Mail)
+	transport, err := mail.NewTransport(cfg.Mail)
+	if err != nil {
+		log.Fatalf("Invalid mail configuration: %v", err)
+	}
+
+	// Обработчики только кладут письма в очередь, отправляет их фоновый воркер
+	emailSender := mail.NewOutbox(repository.DB, transport)
+	emailSender.Start()
+
+	// Пока экземпляр приложения один, события раздаются внутри процесса
+	hub := realtime.NewHub(realtime.NewLocalBroker())
 
-	emailSender := mail.NewGmailSender("BlogPoint", "blogpointoff@gmail.com", "dacqkbzedkdnnxqu")
+	notifier := notify.NewDispatcher(repository.DB, emailSender, hub)
+	notifier.Start()
 
 	app := fiber.New(fiber.Config{
-		BodyLimit: 500 * 1024 * 1024,
+		BodyLimit: cfg.Server.BodyLimitMB * 1024 * 1024,
 	})
 
 	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "http://localhost:5173",
+		AllowOrigins:     strings.Join(cfg.Server.CorsOrigins, ","),
 		AllowCredentials: true,
 	}))
 
-	routes.Setup(app, emailSender)
+	routes.Setup(app, cfg, emailSender, notifier, hub)
 
 	utils.StartCleanupTask()
 	utils.StartStatisticsTask()
+	utils.StartPublishingTask(notifier)
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
-	err := app.Listen(":8000")
+	err = app.Listen(cfg.Server.Port)
 
 	if err != nil {
 		fmt.Printf("fiber.Listen failed %s", err)
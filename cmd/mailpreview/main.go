@@ -0,0 +1,125 @@
+package main
+
+import (
+	"blogpoint-backend/internal/config"
+	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/notify"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// samples — тестовые данные для каждого типа писем
+var samples = map[string]interface{}{
+	mail.TemplateEmailVerification: mail.CodeData{Code: "A1B2C3", ValidMinutes: 10},
+	mail.TemplateAccountDeletion:   mail.CodeData{Code: "D4E5F6", ValidMinutes: 10},
+	mail.TemplateComplaintResolved: mail.ComplaintResolvedData{
+		TargetType:    models.ComplaintTargetPost,
+		ComplaintType: "spam",
+		Resolution:    "The post has been hidden",
+	},
+	notify.TypeCommentReply: models.NotificationData{
+		ActorLogin: "johndoe",
+		PostTitle:  "Today's news",
+		Text:       "Thanks, <b>great</b> post!",
+	},
+	notify.TypeNewSubscriber: models.NotificationData{
+		ActorLogin:  "johndoe",
+		ChannelName: "Go и бэкенд",
+	},
+	notify.TypeNewPost: models.NotificationData{
+		ChannelName: "Go и бэкенд",
+		PostTitle:   "Today's news",
+	},
+}
+
+// Утилита для просмотра писем без отправки:
+//
+//	go run ./cmd/mailpreview -list
+//	go run ./cmd/mailpreview -template password_reset -lang en -part text
+//	go run ./cmd/mailpreview -out ./mail-preview
+func main() {
+	list := flag.Bool("list", false, "вывести типы писем и языки")
+	name := flag.String("template", mail.TemplateEmailVerification, "тип письма")
+	language := flag.String("lang", mail.DefaultLanguage, "язык письма")
+	part := flag.String("part", "html", "часть письма: html, text или subject")
+	out := flag.String("out", "", "каталог, в который сохраняются все письма на всех языках")
+	flag.Parse()
+
+	// Ссылки в письмах ведут на адрес из настроек окружения dev
+	cfg, err := config.Defaults(config.EnvDev)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mail.Configure(cfg.Mail)
+	samples[mail.TemplatePasswordReset] = mail.LinkData{
+		Link:         mail.SiteURL() + "/reset-password?token=A1B2C3",
+		ValidMinutes: 10,
+	}
+
+	switch {
+	case *list:
+		fmt.Println("templates:", mail.TemplateNames())
+		fmt.Println("languages:", mail.Languages())
+	case *out != "":
+		if err := renderAll(*out); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		message, err := render(*name, *language)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch *part {
+		case "html":
+			fmt.Print(message.HTML)
+		case "text":
+			fmt.Print(message.Text)
+		case "subject":
+			fmt.Println(message.Subject)
+		default:
+			log.Fatalf("unknown part %q", *part)
+		}
+	}
+}
+
+func render(name string, language string) (mail.Message, error) {
+	data, ok := samples[name]
+	if !ok {
+		return mail.Message{}, fmt.Errorf("no sample data for template %q", name)
+	}
+	return mail.Render(name, language, data)
+}
+
+// renderAll сохраняет каждое письмо в файлы <тип>.<язык>.html и <тип>.<язык>.txt,
+// тема письма записывается первой строкой текстовой версии
+func renderAll(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, language := range mail.Languages() {
+		for _, name := range mail.TemplateNames() {
+			message, err := render(name, language)
+			if err != nil {
+				return err
+			}
+
+			base := filepath.Join(dir, name+"."+language)
+			if err = os.WriteFile(base+".html", []byte(message.HTML), 0o644); err != nil {
+				return err
+			}
+			text := "Subject: " + message.Subject + "\n\n" + message.Text
+			if err = os.WriteFile(base+".txt", []byte(text), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Println("saved to", dir)
+	return nil
+}
@@ -15,33 +15,28 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/changePassword": {
-            "patch": {
+        "/api/abortChunkedUpload/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Изменение пароля пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Отменяет загрузку и удаляет полученные части",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "File"
                 ],
-                "summary": "Смена пароля",
+                "summary": "Отмена загрузки частями",
                 "parameters": [
                     {
-                        "description": "Старый и новый пароль",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ChangePasswordRequest"
-                        }
+                        "type": "integer",
+                        "description": "Id загрузки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -68,18 +63,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/createChannel": {
+        "/api/addChannelModerator": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создает канал для текущего пользователя",
+                "description": "Назначает пользователя с указанным логином модератором канала. Доступно владельцу канала, модераторам и администраторам платформы.",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,17 +94,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "Moderation"
                 ],
-                "summary": "Создание канал",
+                "summary": "Назначение модератора канала",
                 "parameters": [
                     {
-                        "description": "Данные канала",
+                        "description": "Id канала и логин пользователя",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreateChannelRequest"
+                            "$ref": "#/definitions/controllers.ChannelModeratorRequest"
                         }
                     }
                 ],
@@ -105,7 +112,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelModeratorResponse"
                         }
                     },
                     "400": {
@@ -119,50 +126,48 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/createComment": {
-            "post": {
+        "/api/admin/getRoles": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создает новый комментарий к посту или ответ на существующий комментарий",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает все роли платформы. Доступно только администраторам.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
-                ],
-                "summary": "Создание комментария",
-                "parameters": [
-                    {
-                        "description": "Данные комментария",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateCommentRequest"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "Получение ролей",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-models_Comment"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-array_models_Role"
                         }
                     },
                     "401": {
@@ -171,8 +176,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -186,14 +191,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/createPost": {
-            "post": {
+        "/api/admin/setUserRole": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создает пост в указанном канале. Пользователь должен быть владельцем канала.",
+                "description": "Назначает пользователю роль user, moderator или admin. Доступно только администраторам, снять роль с себя нельзя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -201,17 +206,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Post"
+                    "Admin"
                 ],
-                "summary": "Создание поста",
+                "summary": "Назначение роли",
                 "parameters": [
                     {
-                        "description": "Данные поста (channelId, title, content, tags)",
+                        "description": "Пользователь и новая роль",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreatePostRequest"
+                            "$ref": "#/definitions/controllers.SetUserRoleRequest"
                         }
                     }
                 ],
@@ -219,7 +224,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -246,8 +251,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -255,14 +260,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deleteChannel/{id}": {
-            "delete": {
+        "/api/changePassword": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет канал, если пользователь является его владельцем",
+                "description": "Изменение пароля пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -270,16 +275,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "User"
                 ],
-                "summary": "Удаление канала",
+                "summary": "Смена пароля",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID канала",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Старый и новый пароль",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ChangePasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -301,46 +308,34 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/api/deleteChannelLogo/{id}": {
-            "delete": {
+        "/api/claimComplaint/{id}": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет текущий логотип канала и очищает поле logo_id",
+                "description": "Назначает текущего модератора исполнителем открытой жалобы и переводит ее в статус in progress",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "Complaint"
                 ],
-                "summary": "Удаление логотипа канала",
+                "summary": "Взять жалобу в работу",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Id канала",
+                        "description": "Id жалобы",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -350,7 +345,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ComplaintResponse"
                         }
                     },
                     "400": {
@@ -365,6 +360,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -374,25 +387,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deleteComment/{id}": {
-            "delete": {
+        "/api/completeChunkedUpload/{id}": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет комментарий, если нет ответов — полностью, иначе помечает как удаленный",
+                "description": "Собирает файл из всех частей и проверяет его так же, как /api/completeUpload. Если получены не все части, возвращается 409 и загрузку можно продолжить",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "File"
                 ],
-                "summary": "Удаление комментария",
+                "summary": "Завершение загрузки частями",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Id комментария",
+                        "description": "Id загрузки",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -402,7 +415,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
                         }
                     },
                     "400": {
@@ -417,14 +430,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -438,25 +463,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deleteFile/{id}": {
-            "delete": {
+        "/api/completeUpload/{id}": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет файл по id",
+                "description": "Проверяет, что файл загружен в хранилище, его размер совпадает с заявленным, а тип по содержимому — с разрешенной категорией, и создает запись файла. Изображения обрабатываются как в /api/uploadFile. Файл, не прошедший проверку, удаляется; если файл еще не загружен, возвращается 409 и подтверждение можно повторить",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "File"
                 ],
-                "summary": "Удаление файла",
+                "summary": "Подтверждение загрузки файла",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Id файла для удаления",
+                        "description": "Id загрузки",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -466,7 +491,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
                         }
                     },
                     "400": {
@@ -481,14 +506,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -502,14 +539,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deletePost/{id}": {
-            "delete": {
+        "/api/confirmTotp": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет пост, если пользователь — владелец канала",
+                "description": "Проверяет код из приложения-аутентификатора, включает вход с кодом и возвращает коды восстановления. Коды показываются один раз",
                 "consumes": [
                     "application/json"
                 ],
@@ -517,23 +554,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Post"
+                    "Auth"
                 ],
-                "summary": "Удаление поста",
+                "summary": "Подтверждение TOTP",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Id поста",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Код TOTP",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Сообщение об успешном удалении",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_RecoveryCodesResponse"
                         }
                     },
                     "400": {
@@ -548,14 +587,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -563,14 +608,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deleteUser": {
-            "delete": {
+        "/api/createChannel": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет аккаунт пользователя по коду подтверждения",
+                "description": "Создает канал для текущего пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -578,17 +623,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Channel"
                 ],
-                "summary": "Удаление аккаунта",
+                "summary": "Создание канал",
                 "parameters": [
                     {
-                        "description": "Код подтверждения",
+                        "description": "Данные канала",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CodeRequest"
+                            "$ref": "#/definitions/controllers.CreateChannelRequest"
                         }
                     }
                 ],
@@ -596,7 +641,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
                         }
                     },
                     "400": {
@@ -614,29 +659,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/deleteUserLogo": {
-            "delete": {
+        "/api/createComment": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет текущее лого пользователя и очищает поле logo_id",
+                "description": "Создает новый комментарий к посту или ответ на существующий комментарий",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Comment"
+                ],
+                "summary": "Создание комментария",
+                "parameters": [
+                    {
+                        "description": "Данные комментария",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateCommentRequest"
+                        }
+                    }
                 ],
-                "summary": "Удаление лого пользователя",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-models_Comment"
                         }
                     },
                     "400": {
@@ -651,6 +707,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -660,14 +722,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/editChannel": {
-            "patch": {
+        "/api/createComplaint": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Изменяет название и описание канала пользователя",
+                "description": "Создает жалобу на канал, пост или комментарий. Типы жалоб: spam, abuse, illegal, misinformation, other. Повторная открытая жалоба на тот же объект не принимается.",
                 "consumes": [
                     "application/json"
                 ],
@@ -675,17 +737,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "Complaint"
                 ],
-                "summary": "Редактироввние канала",
+                "summary": "Отправка жалобы",
                 "parameters": [
                     {
-                        "description": "Обновленные данные канала",
+                        "description": "Объект и описание жалобы",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.EditChannelRequest"
+                            "$ref": "#/definitions/controllers.CreateComplaintRequest"
                         }
                     }
                 ],
@@ -693,7 +755,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ComplaintResponse"
                         }
                     },
                     "400": {
@@ -708,12 +770,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -729,14 +785,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/editPost": {
-            "patch": {
+        "/api/createPost": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Изменяет заголовок, содержимое и теги поста. Пользователь должен быть владельцем канала.",
+                "description": "Создает пост в указанном канале. Пользователь должен быть владельцем канала. Текст может быть в формате plain (по умолчанию) или markdown; изображения, прикрепленные к посту, вставляются в markdown ссылкой вида ![подпись](image:Id). По умолчанию пост публикуется сразу; со статусом draft сохраняется черновик, со статусом scheduled и временем publishAt пост будет опубликован автоматически.",
                 "consumes": [
                     "application/json"
                 ],
@@ -746,15 +802,15 @@ const docTemplate = `{
                 "tags": [
                     "Post"
                 ],
-                "summary": "Редактирование поста",
+                "summary": "Создание поста",
                 "parameters": [
                     {
-                        "description": "Данные для обновления поста (postId, title?, content?, tags?)",
+                        "description": "Данные поста (channelId, title, content, tags, status?, publishAt?)",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.EditPostRequest"
+                            "$ref": "#/definitions/controllers.CreatePostRequest"
                         }
                     }
                 ],
@@ -788,18 +844,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/editProfile": {
-            "patch": {
+        "/api/deleteChannel/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Изменение профиля пользователя",
+                "description": "Удаляет канал, если пользователь является его владельцем, модератором или администратором",
                 "consumes": [
                     "application/json"
                 ],
@@ -807,25 +869,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Channel"
                 ],
-                "summary": "Редактирование профиля",
+                "summary": "Удаление канала",
                 "parameters": [
                     {
-                        "description": "Новые данные профиля",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.EditProfileRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID канала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_UserResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "401": {
@@ -833,25 +899,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/getAllCategories": {
-            "get": {
-                "description": "Получение всех доступных категорий",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tags and categories"
-                ],
-                "summary": "Список категорий",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_CategoryResponse"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -863,21 +921,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getAllTags": {
-            "get": {
-                "description": "Получение всех тегов с цветом соответствующей категории",
+        "/api/deleteChannelLogo/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет текущий логотип канала и очищает поле logo_id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tags and categories"
+                    "Channel"
+                ],
+                "summary": "Удаление логотипа канала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Список тегов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_TagResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -889,23 +973,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getChannel/{id}": {
-            "get": {
-                "description": "Возвращает информацию о канале по ID",
-                "consumes": [
-                    "application/json"
+        "/api/deleteComment/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Удаляет комментарий, если нет ответов — полностью, иначе помечает как удаленный. Доступно автору, модераторам и администраторам",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "Comment"
                 ],
-                "summary": "Получение канала",
+                "summary": "Удаление комментария",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID канала",
+                        "description": "Id комментария",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -915,7 +1001,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -924,6 +1010,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -939,44 +1037,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getChannelStatistics/{id}": {
-            "get": {
+        "/api/deleteFile/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает статистику канала за указанный период",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Удаляет файл по id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "File"
                 ],
-                "summary": "Получить статистику канала",
+                "summary": "Удаление файла",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Id канала",
+                        "description": "Id файла для удаления",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Период получения статистики (по умолчанию ",
-                        "name": "period",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_StatisticsResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -991,33 +1080,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/getPopularChannels": {
-            "get": {
-                "description": "Возвращает список каналов, отсортированных по количеству подписчиков по убыванию",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Channel"
-                ],
-                "summary": "Получение популярных каналов",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.DataResponse-array_controllers_ChannelResponse"
-                            }
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1029,9 +1101,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getPost/{id}": {
-            "get": {
-                "description": "Возвращает пост с тегами по Id",
+        "/api/deletePost/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет пост, если пользователь — владелец канала, модератор или администратор",
                 "consumes": [
                     "application/json"
                 ],
@@ -1041,7 +1118,7 @@ const docTemplate = `{
                 "tags": [
                     "Post"
                 ],
-                "summary": "Получение поста",
+                "summary": "Удаление поста",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1053,9 +1130,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Сообщение об успешном удалении",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -1064,18 +1141,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "/api/getPostComments": {
-            "get": {
-                "description": "Возвращает комментарии к посту, поддерживает пагинацию и фильтрацию по parentId",
+        "/api/deleteUser": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет аккаунт пользователя по коду подтверждения. После 5 неверных вводов код аннулируется",
                 "consumes": [
                     "application/json"
                 ],
@@ -1083,41 +1177,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comment"
+                    "User"
                 ],
-                "summary": "Получение комментариев",
+                "summary": "Удаление аккаунта",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Id поста",
-                        "name": "postId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Id родительского комментария (для подкомментариев)",
-                        "name": "parentId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение (для пагинации)",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Количество комментариев на странице",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Код подтверждения",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_CommentResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -1126,8 +1204,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1135,42 +1219,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getPosts/{channelId}": {
-            "get": {
-                "description": "Получает список постов по Id канала с пагинацией (по 10 на страницу)",
+        "/api/deleteUserLogo": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет текущее лого пользователя и очищает поле logo_id",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Post"
-                ],
-                "summary": "Get posts",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Id канала",
-                        "name": "channelId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Номер страницы (по умолчанию 1)",
-                        "name": "page",
-                        "in": "query"
-                    }
+                    "User"
                 ],
+                "summary": "Удаление лого пользователя",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.DataResponse-array_controllers_PostResponse"
-                            }
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -1179,6 +1250,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1188,9 +1265,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getRecommendedPosts": {
-            "get": {
-                "description": "Получает список рекомендуемых постов за последнюю неделю, сортируя по формуле: views + likes*3 - dislikes*2 + comments*2",
+        "/api/disableTotp": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отключает вход с кодом и удаляет коды восстановления. Требуется текущий код TOTP или код восстановления",
                 "consumes": [
                     "application/json"
                 ],
@@ -1198,22 +1280,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Post"
+                    "Auth"
                 ],
-                "summary": "Рекомендуемые посты",
+                "summary": "Отключение TOTP",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Номер страницы (по умолчанию 1)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Код TOTP или код восстановления",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_PostResponse"
+                            "$ref": "#/definitions/controllers.MessageResponse"
                         }
                     },
                     "400": {
@@ -1222,42 +1307,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/getUserChannels": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Возвращает список каналов, созданных текущим пользователем",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Channel"
-                ],
-                "summary": "Получение каналов пользователя",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.DataResponse-array_controllers_ChannelResponse"
-                            }
-                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1271,29 +1328,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/getUserSubscriptions": {
-            "get": {
+        "/api/editChannel": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает список каналов, на которые подписан текущий пользователь",
+                "description": "Изменяет название и описание канала пользователя",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Channel"
                 ],
-                "summary": "Получение подписок пользователя",
+                "summary": "Редактироввние канала",
+                "parameters": [
+                    {
+                        "description": "Обновленные данные канала",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.EditChannelRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.DataResponse-array_controllers_ChannelResponse"
-                            }
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1302,6 +1376,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1311,14 +1397,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/languageUpdate": {
+        "/api/editPost": {
             "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Изменение языка интерфейса пользователя",
+                "description": "Изменяет заголовок, содержимое и теги поста. Каждая правка сохраняется в истории изменений. Доступно владельцу канала, модераторам и администраторам.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1326,17 +1412,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Post"
                 ],
-                "summary": "Смена языка интерфейса",
+                "summary": "Редактирование поста",
                 "parameters": [
                     {
-                        "description": "Новый язык интерфейса",
+                        "description": "Данные для обновления поста (postId, title?, content?, tags?)",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.LanguageUpdateRequest"
+                            "$ref": "#/definitions/controllers.EditPostRequest"
                         }
                     }
                 ],
@@ -1344,7 +1430,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
                         }
                     },
                     "400": {
@@ -1359,6 +1445,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1368,9 +1460,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/login": {
-            "post": {
-                "description": "Авторизация пользователя",
+        "/api/editProfile": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменение профиля пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -1378,17 +1475,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "User"
                 ],
-                "summary": "Авторизация",
+                "summary": "Редактирование профиля",
                 "parameters": [
                     {
-                        "description": "Логин и пароль",
+                        "description": "Новые данные профиля",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.LoginRequest"
+                            "$ref": "#/definitions/controllers.EditProfileRequest"
                         }
                     }
                 ],
@@ -1396,17 +1493,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_UserResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1414,55 +1505,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/logout": {
-            "post": {
-                "description": "Удаляет JWT cookie и завершает сессию",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Выход из аккаунта",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
-                        }
+        "/api/feed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
-            }
-        },
-        "/api/register": {
-            "post": {
-                "description": "Регистрация нового пользователя",
-                "consumes": [
-                    "application/json"
                 ],
+                "description": "Возвращает опубликованные посты из каналов, на которые подписан пользователь. В режиме latest посты идут от новых к старым по времени публикации, в режиме ranked учитывается рейтинг из рекомендаций (views + likes*3 - dislikes*2 + comments*2) вместе со свежестью поста. Рейтинг пересчитывается на каждый запрос, поэтому страницы режима ranked приблизительные: пост может повториться или выпасть при переходе по курсору. Поле isSeen показывает, отмечен ли пост просмотренным.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Feed"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Лента подписок",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "Режим: latest или ranked (по умолчанию latest)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только непросмотренные посты",
+                        "name": "unseen",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_FeedPostResponse"
                         }
                     },
                     "400": {
@@ -1470,34 +1558,41 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/requestDeletionVerification": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Отправляет код подтверждения на email для удаления аккаунта",
+        "/api/getAllCategories": {
+            "get": {
+                "description": "Получение всех доступных категорий",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tags and categories"
                 ],
-                "summary": "Отправить код подтверждения удаления аккаунта",
+                "summary": "Список категорий",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_CategoryResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1505,30 +1600,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/requestEmailVerification": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Отправление кода на почту для её подтверждения",
+        "/api/getAllTags": {
+            "get": {
+                "description": "Получение всех тегов с цветом соответствующей категории",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tags and categories"
                 ],
-                "summary": "Запрос кода подтверждения email",
+                "summary": "Список тегов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_TagResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1536,9 +1626,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/requestPasswordReset": {
-            "post": {
-                "description": "Отправление ссылки для сброса пароля на почту пользователя",
+        "/api/getChannel/{id}": {
+            "get": {
+                "description": "Возвращает информацию о канале по ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1546,25 +1636,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Channel"
                 ],
-                "summary": "Запрос на сброс пароля",
+                "summary": "Получение канала",
                 "parameters": [
                     {
-                        "description": "Email пользователя",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.EmailRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID канала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
                         }
                     },
                     "400": {
@@ -1578,43 +1666,9 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/resetPassword": {
-            "patch": {
-                "description": "Сброс пароля по коду, отправленному на почту",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Сброс пароля",
-                "parameters": [
-                    {
-                        "description": "Код и новый пароль",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ResetPasswordRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1622,50 +1676,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/setReaction": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Устанавливает реакцию (лайк/дизлайк) пользователя на пост. Повторное нажатие удаляет реакцию",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/getChannelBySlug/{slug}": {
+            "get": {
+                "description": "Возвращает информацию о канале по слагу. Если канал переименован, со старого слага отвечает 301 с адресом по текущему слагу.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Post"
+                    "Channel"
                 ],
-                "summary": "Set reaction to post",
+                "summary": "Получение канала по слагу",
                 "parameters": [
                     {
-                        "description": "Данные реакции",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.SetReactionRequest"
-                        }
+                        "type": "string",
+                        "description": "Слаг канала",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChannelResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "301": {
+                        "description": "Перенаправление на текущий адрес канала",
                         "schema": {
-                            "$ref": "#/definitions/controllers.ErrorResponse"
+                            "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1679,24 +1723,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/subscribeChannel/{id}": {
-            "post": {
+        "/api/getChannelModerators/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Подписывает пользователя на указанный канал",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает список модераторов канала. Доступно тем, кто может модерировать канал.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "Moderation"
                 ],
-                "summary": "Подписка на канал",
+                "summary": "Модераторы канала",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1710,7 +1751,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_ChannelModeratorResponse"
                         }
                     },
                     "400": {
@@ -1725,14 +1766,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
@@ -1740,14 +1787,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/unsubscribeChannel/{id}": {
-            "delete": {
+        "/api/getChannelStatistics/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Отписывает пользователя от указанного канала",
+                "description": "Возвращает статистику канала за указанный период",
                 "consumes": [
                     "application/json"
                 ],
@@ -1757,7 +1804,7 @@ const docTemplate = `{
                 "tags": [
                     "Channel"
                 ],
-                "summary": "Отписаться от канала",
+                "summary": "Получить статистику канала",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1765,13 +1812,19 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Период получения статистики (по умолчанию ",
+                        "name": "period",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_StatisticsResponse"
                         }
                     },
                     "400": {
@@ -1795,45 +1848,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/uploadChannelLogo/{id}": {
-            "post": {
+        "/api/getChunkedUpload/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Загружает изображение и устанавливает его как логотип канала",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Возвращает номера полученных частей и offset — число байт, полученных подряд с начала файла. После обрыва связи загрузку продолжают с первой недостающей части",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Channel"
+                    "File"
                 ],
-                "summary": "Загрузка логотипа канала",
+                "summary": "Состояние загрузки частями",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Id канала",
+                        "description": "Id загрузки",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Файл изображения",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChunkedUploadResponse"
                         }
                     },
                     "400": {
@@ -1848,53 +1891,156 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/uploadFile": {
-            "post": {
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getComplaints": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Загружает файл и возвращает его Id и URL",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Возвращает жалобы с фильтрами по статусу, типу объекта, типу жалобы и исполнителю, старые сначала, с курсорной пагинацией. Доступно модераторам и администраторам.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Complaint"
                 ],
-                "summary": "Загрузка файла",
+                "summary": "Список жалоб",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Тип файла",
-                        "name": "type",
+                        "description": "Статус: open, in progress, close",
+                        "name": "status",
                         "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "Файл для загрузки",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Тип объекта: channel, post, comment",
+                        "name": "targetType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип жалобы",
+                        "name": "complaintType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "me — только взятые текущим модератором, none — невзятые",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_ComplaintResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getFileContent/{id}": {
+            "get": {
+                "description": "Отдает файл через API с поддержкой заголовка Range (один диапазон байт) для перемотки аудио и видео. Закрытый файл доступен владельцу, персоналу платформы и тем, кто может видеть пост в канале владельца, к которому он прикреплен; остальным возвращается 404. Используется, когда подписанные ссылки недоступны",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Содержимое файла",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id файла",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вариант изображения, например preview_640",
+                        "name": "variant",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Диапазон байт, например bytes=0-1048575",
+                        "name": "Range",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.FileResponse"
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -1903,6 +2049,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1912,38 +2070,84 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/uploadUserLogo": {
-            "post": {
+        "/api/getIdentities": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Загружает изображение и устанавливает его как лого текущего авторизованного пользователя.",
-                "consumes": [
-                    "multipart/form-data"
+                "description": "Возвращает аккаунты OIDC провайдеров, через которые можно войти в аккаунт текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Привязанные аккаунты",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_IdentityResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getModerationLog/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Возвращает действия модераторов в канале, новые сначала, с курсорной пагинацией. Доступно владельцу канала, модераторам и администраторам платформы.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Moderation"
                 ],
-                "summary": "Загрузка лого пользователя",
+                "summary": "Журнал модерации",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Файл изображения",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_ModerationLogResponse"
                         }
                     },
                     "400": {
@@ -1958,6 +2162,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1967,26 +2183,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/user": {
+        "/api/getNotificationPreferences": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение данных авторизованного пользователя",
+                "description": "Возвращает для каждого типа уведомлений, показывать ли его в приложении (inApp) и отправлять ли на почту (email). По умолчанию на почту приходят только ответы на комментарии.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Notification"
                 ],
-                "summary": "Получение данных пользователя",
+                "summary": "Настройки уведомлений",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.DataResponse-controllers_UserResponse"
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_NotificationPreferenceResponse"
                         }
                     },
                     "401": {
@@ -1994,44 +2210,56 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/verifyEmail": {
-            "post": {
+        "/api/getNotifications": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Подтверждение email полученным кодом",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает уведомления текущего пользователя, новые сначала, с курсорной пагинацией. Типы: comment_reply — ответ на комментарий, new_subscriber — новый подписчик канала, new_post — новый пост в канале из подписок.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Notification"
                 ],
-                "summary": "Подтверждение email",
+                "summary": "Список уведомлений",
                 "parameters": [
                     {
-                        "description": "Код подтверждения",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CodeRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Только непрочитанные",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_NotificationResponse"
                         }
                     },
                     "400": {
@@ -2045,227 +2273,3989 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.CategoryResponse": {
-            "type": "object",
-            "properties": {
-                "color": {
-                    "type": "string",
-                    "example": "#FF9800"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Личный блог"
-                }
-            }
         },
-        "controllers.ChangePasswordRequest": {
-            "type": "object",
-            "properties": {
-                "newPassword": {
-                    "type": "string",
-                    "example": "newSecret123"
-                },
-                "oldPassword": {
-                    "type": "string",
-                    "example": "oldSecret123"
-                }
-            }
+        "/api/getPopularChannels": {
+            "get": {
+                "description": "Возвращает список каналов, отсортированных по количеству подписчиков по убыванию, с курсорной пагинацией",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Channel"
+                ],
+                "summary": "Получение популярных каналов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_ChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getPost/{id}": {
+            "get": {
+                "description": "Возвращает пост с тегами по Id. Неопубликованные посты видны только тем, кто может управлять каналом.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Получение поста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id поста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getPostBySlug/{channelSlug}/{postSlug}": {
+            "get": {
+                "description": "Возвращает пост по слагу канала и слагу поста. Если канал или пост переименованы, со старого слага отвечает 301 с адресом по текущим слагам.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Получение поста по слагу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Слаг канала",
+                        "name": "channelSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Слаг поста",
+                        "name": "postSlug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
+                        }
+                    },
+                    "301": {
+                        "description": "Перенаправление на текущий адрес поста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getPostComments": {
+            "get": {
+                "description": "Возвращает комментарии к посту в порядке создания, поддерживает курсорную пагинацию и фильтрацию по parentId. Комментарии скрытого или неопубликованного поста доступны только тем, кто может видеть сам пост",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "Получение комментариев",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id поста",
+                        "name": "postId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id родительского комментария (для подкомментариев)",
+                        "name": "parentId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getPostRevisionDiff/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает разницу между двумя ревизиями поста: заголовок сравнивается посимвольно, текст — построчно, для тегов, изображений и файлов возвращаются добавленные и удаленные Id. Если to не указан, сравнение идет с последней ревизией. Доступно тем, кто может управлять постами канала.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Сравнение ревизий поста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id поста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id исходной ревизии",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id конечной ревизии",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostRevisionDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getPostRevisions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает ревизии поста с полным содержимым, новые сначала, с курсорной пагинацией. Доступно тем, кто может управлять постами канала.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "История изменений поста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id поста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_PostRevisionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getPosts/{channelId}": {
+            "get": {
+                "description": "Получает список постов по Id канала, новые сначала, с курсорной пагинацией",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Get posts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "channelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус: published (по умолчанию), draft, scheduled, archived. Неопубликованные посты доступны тем, кто может управлять каналом",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_PostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getRecommendedPosts": {
+            "get": {
+                "description": "Получает список рекомендуемых постов за последнюю неделю, сортируя по формуле: views + likes*3 - dislikes*2 + comments*2. Рейтинг пересчитывается на каждый запрос, поэтому страницы приблизительные: пост может повториться или выпасть при переходе по курсору",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Рекомендуемые посты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_PostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getSessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список активных сессий текущего пользователя (устройство, IP, время создания и последней активности)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Активные сессии",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_SessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getUnreadNotificationsCount": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает количество непрочитанных уведомлений текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification"
+                ],
+                "summary": "Число непрочитанных уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-int64"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getUserChannels": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список каналов, созданных текущим пользователем",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Channel"
+                ],
+                "summary": "Получение каналов пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.DataResponse-array_controllers_ChannelResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/getUserSubscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список каналов, на которые подписан текущий пользователь, последние подписки сначала",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Channel"
+                ],
+                "summary": "Получение подписок пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор из nextCursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 10, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PageResponse-array_controllers_ChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/languageUpdate": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменение языка интерфейса пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Смена языка интерфейса",
+                "parameters": [
+                    {
+                        "description": "Новый язык интерфейса",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LanguageUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/login": {
+            "post": {
+                "description": "Авторизация пользователя. После 5 неверных паролей подряд аккаунт блокируется на минуту, каждая следующая ошибка удваивает блокировку (до часа). Запросы с одного IP ограничены, состояние лимита возвращается в заголовках X-RateLimit-*. Если включена двухфакторная аутентификация, cookie не выставляются, а в ответе приходит mfaToken для /api/loginMfa",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Авторизация",
+                "parameters": [
+                    {
+                        "description": "Логин и пароль",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/loginMfa": {
+            "post": {
+                "description": "Обменивает mfaToken из ответа /api/login и код TOTP (или неиспользованный код восстановления) на сессию. Неверные коды учитываются в блокировке входа так же, как неверные пароли",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Второй шаг входа",
+                "parameters": [
+                    {
+                        "description": "Токен и код",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginMfaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/logout": {
+            "post": {
+                "description": "Отзывает серверную сессию и удаляет cookie с токенами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Выход из аккаунта",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/markFeedSeen": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отмечает посты просмотренными текущим пользователем, чтобы скрывать их в ленте с параметром unseen. Несуществующие и уже отмеченные посты пропускаются. За один запрос — не больше 100 постов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Отметка просмотренных постов",
+                "parameters": [
+                    {
+                        "description": "Id просмотренных постов",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MarkFeedSeenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/markNotificationsRead": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отмечает прочитанными перечисленные уведомления (не больше 100 за запрос) или, при all = true, все уведомления пользователя. Чужие уведомления пропускаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification"
+                ],
+                "summary": "Отметка уведомлений прочитанными",
+                "parameters": [
+                    {
+                        "description": "Id уведомлений или признак all",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MarkNotificationsReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/oidc/providers": {
+            "get": {
+                "description": "Возвращает настроенных OIDC провайдеров и адреса, с которых начинается вход",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Провайдеры входа",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_OIDCProviderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/oidc/{provider}/callback": {
+            "get": {
+                "description": "Обменивает код авторизации на ID токен и перенаправляет на страницу фронтенда. Пользователь находится по привязанному аккаунту провайдера или по подтвержденному провайдером email, иначе создается новый. При успехе выставляются cookie сессии; если включена двухфакторная аутентификация, в адресе передается mfaToken для /api/loginMfa, при ошибке — параметр error",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Возврат от провайдера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код авторизации",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние из запроса входа",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/oidc/{provider}/login": {
+            "get": {
+                "description": "Перенаправляет браузер к OIDC провайдеру (authorization code flow с PKCE). С параметром link=true аккаунт провайдера привязывается к текущему пользователю, для этого нужна авторизация",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Вход через провайдера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Привязать к текущему пользователю",
+                        "name": "link",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/refresh": {
+            "post": {
+                "description": "Выдает новый access токен и ротирует refresh токен из cookie. Повторное использование старого refresh токена отзывает сессию. Если токен только что заменил параллельный запрос (в пределах 30 секунд), возвращается 409 без отзыва сессии: новые cookie уже выданы этим запросом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Обновление токенов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/regenerateRecoveryCodes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет все коды восстановления новыми. Требуется текущий код TOTP",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Новые коды восстановления",
+                "parameters": [
+                    {
+                        "description": "Код TOTP",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/register": {
+            "post": {
+                "description": "Регистрация нового пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/removeChannelModerator/{channelId}/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает пользователя с модерации канала. Доступно владельцу канала, модераторам и администраторам платформы, а также самому модератору.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Снятие модератора канала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "channelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id модератора",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/requestDeletionVerification": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправляет код подтверждения на email для удаления аккаунта. Число писем на аккаунт ограничено",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Отправить код подтверждения удаления аккаунта",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/requestEmailVerification": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправление кода на почту для её подтверждения. Число писем на аккаунт ограничено",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Запрос кода подтверждения email",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/requestPasswordReset": {
+            "post": {
+                "description": "Отправление ссылки для сброса пароля на почту пользователя. Число писем на один email ограничено",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Запрос на сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Email пользователя",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.EmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/requestUpload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает подписанную ссылку для загрузки файла в хранилище запросом PUT, минуя API. Тип и размер файла входят в подпись: хранилище примет только файл с указанными Content-Type и Content-Length. Ссылка действует час, после загрузки ее нужно подтвердить через /api/completeUpload/{uploadId} в течение суток",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Ссылка для загрузки файла",
+                "parameters": [
+                    {
+                        "description": "Имя, размер и тип файла",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RequestUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_UploadUrlResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resetPassword": {
+            "patch": {
+                "description": "Сброс пароля по токену из ссылки, отправленной на почту",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Токен и новый пароль",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/resolveComplaint": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Закрывает жалобу с текстом решения. Действие hide скрывает пост или комментарий, none оставляет объект без изменений. Закрыть можно свою или невзятую жалобу, администратор может закрыть любую. Автор жалобы получает письмо с решением.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Complaint"
+                ],
+                "summary": "Закрытие жалобы",
+                "parameters": [
+                    {
+                        "description": "Решение по жалобе",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResolveComplaintRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ComplaintResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restorePostRevision": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает посту заголовок, текст, превью, теги, изображения и файлы из выбранной ревизии. Удаленные с тех пор файлы и теги пропускаются. Восстановление сохраняется как новая ревизия. Доступно тем, кто может управлять постами канала.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Восстановление ревизии поста",
+                "parameters": [
+                    {
+                        "description": "Id поста и ревизии",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RestorePostRevisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/revokeOtherSessions": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает все сессии текущего пользователя, кроме той, из которой выполнен запрос",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Выход на других устройствах",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/revokeSession/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает указанную сессию текущего пользователя, например на потерянном устройстве",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Завершение сессии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/search": {
+            "get": {
+                "description": "Полнотекстовый поиск по заголовкам и тексту постов, названиям и описаниям каналов с учетом морфологии. Язык берется из параметра lang, иначе из профиля пользователя, по умолчанию ru. Результаты отсортированы по релевантности, совпадения во фрагментах выделены тегом mark.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Поиск",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос (поддерживает кавычки, OR и минус)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Что искать: all, posts, channels (по умолчанию all)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык морфологии: ru или en",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id категории",
+                        "name": "categoryId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id тега (только посты)",
+                        "name": "tagId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id канала (только посты)",
+                        "name": "channelId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода публикации, RFC 3339 или YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода публикации (не включая), RFC 3339 или YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/setFileVisibility/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Делает файл публичным (постоянная ссылка) или закрытым (временные подписанные ссылки). Файл и его варианты переносятся в хранилище, поэтому прежние ссылки перестают действовать",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Изменение видимости файла",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id файла",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Видимость: public или private",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SetFileVisibilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/setPostHidden": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает пост с публикации или возвращает его. Доступно владельцу канала, модераторам канала, модераторам и администраторам платформы.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Скрытие поста",
+                "parameters": [
+                    {
+                        "description": "Id поста и признак скрытия",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SetPostHiddenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/setPostStatus": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит пост между статусами draft, scheduled, published и archived. Для статуса scheduled нужно время publishAt в будущем. Опубликованный пост можно только архивировать, архивный — опубликовать снова. Доступно тем, кто может управлять постами канала.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Изменение статуса поста",
+                "parameters": [
+                    {
+                        "description": "Id поста, новый статус и время публикации",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SetPostStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_PostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/setReaction": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Устанавливает реакцию (лайк/дизлайк) пользователя на пост. Повторное нажатие удаляет реакцию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "Set reaction to post",
+                "parameters": [
+                    {
+                        "description": "Данные реакции",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SetReactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/setupTotp": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новый секрет TOTP и возвращает его вместе с URI otpauth:// и QR-кодом для приложения-аутентификатора. Вход с кодом включается только после подтверждения через /api/confirmTotp",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Подключение TOTP",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_TotpSetupResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/startChunkedUpload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Начинает загрузку файла через API частями по chunkSize байт (последняя часть — остаток), чтобы прерванную загрузку можно было продолжить. Части отправляются через /api/uploadChunk/{uploadId}/{chunk}, загрузка завершается через /api/completeChunkedUpload/{uploadId} в течение суток",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Начало загрузки частями",
+                "parameters": [
+                    {
+                        "description": "Имя, размер и тип файла",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RequestUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_ChunkedUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stream": {
+            "get": {
+                "description": "Открывает поток Server-Sent Events. Для постов из параметра posts приходят события comment.created (новый комментарий, данные как в getPostComments) и reaction.updated (счетчики лайков и дизлайков). Авторизованный по jwt cookie пользователь также получает notification.created о своих новых уведомлениях. Клиент, который не успевает читать события, отключается событием overflow и должен переподключиться.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Stream"
+                ],
+                "summary": "Поток событий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id постов через запятую (не больше 20)",
+                        "name": "posts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток событий",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/subscribeChannel/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подписывает пользователя на указанный канал",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Channel"
+                ],
+                "summary": "Подписка на канал",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/unlinkIdentity/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отвязывает аккаунт OIDC провайдера от текущего пользователя. Пользователь, созданный через провайдера, может задать пароль через сброс пароля",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Отвязка аккаунта провайдера",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID привязки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/unsubscribeChannel/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отписывает пользователя от указанного канала",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Channel"
+                ],
+                "summary": "Отписаться от канала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/updateNotificationPreferences": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сохраняет настройки доставки для перечисленных типов уведомлений, остальные типы не меняются. Письма отправляются только на подтвержденную почту.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification"
+                ],
+                "summary": "Изменение настроек уведомлений",
+                "parameters": [
+                    {
+                        "description": "Настройки по типам уведомлений",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-array_controllers_NotificationPreferenceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploadChannelLogo/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загружает изображение и устанавливает его как логотип канала. Создаются квадратные варианты avatar_64, avatar_128 и avatar_256",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Channel"
+                ],
+                "summary": "Загрузка логотипа канала",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id канала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл изображения",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploadChunk/{id}/{chunk}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принимает часть с номером chunk (с 1) в теле запроса. Размер части — chunkSize, последней — остаток файла. Повторно отправленная часть заменяет прежнюю. Если передан заголовок Content-MD5, хранилище сверяет с ним содержимое части",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Загрузка части файла",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Id загрузки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер части, начиная с 1",
+                        "name": "chunk",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploadFile": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загружает файл и возвращает его Id и URL. Из изображений JPEG, PNG и WebP удаляются метаданные (EXIF, XMP), PNG и WebP сохраняются в PNG или в WebP без потерь (media.webp), для них создаются варианты preview_640 и preview_1280 (только меньше оригинала), их URL возвращаются в variants",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Загрузка файла",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип файла",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Видимость файла: public (по умолчанию) или private",
+                        "name": "visibility",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл для загрузки",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.FileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploadUserLogo": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загружает изображение и устанавливает его как лого текущего авторизованного пользователя. Создаются квадратные варианты avatar_64, avatar_128 и avatar_256",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Загрузка лого пользователя",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл изображения",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_FileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение данных авторизованного пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Получение данных пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DataResponse-controllers_UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/verifyEmail": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подтверждение email полученным кодом. После 5 неверных вводов код аннулируется",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Подтверждение email",
+                "parameters": [
+                    {
+                        "description": "Код подтверждения",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#FF9800"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Личный блог"
+                }
+            }
+        },
+        "controllers.ChangePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "example": "newSecret123"
+                },
+                "oldPassword": {
+                    "type": "string",
+                    "example": "oldSecret123"
+                }
+            }
+        },
+        "controllers.ChannelModeratorRequest": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "login": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "controllers.ChannelModeratorResponse": {
+            "type": "object",
+            "properties": {
+                "assignedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "login": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "controllers.ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.Category"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "logo": {
+                    "$ref": "#/definitions/controllers.FileResponse"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "novosti-tekhnologiy"
+                },
+                "subsCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.ChannelSearchResult": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "$ref": "#/definitions/controllers.ChannelResponse"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.31
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "Новости о \u003cmark\u003eGo\u003c/mark\u003e и бэкенде"
+                }
+            }
+        },
+        "controllers.ChannelStatistics": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "dislikes": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "likes": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "posts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "views": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "controllers.ChunkedUploadResponse": {
+            "type": "object",
+            "properties": {
+                "chunkSize": {
+                    "type": "integer",
+                    "example": 8388608
+                },
+                "chunks": {
+                    "type": "integer",
+                    "example": 38
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 25165824
+                },
+                "receivedChunks": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 314572800
+                },
+                "uploadId": {
+                    "type": "integer",
+                    "example": 13
+                }
+            }
+        },
+        "controllers.CodeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "H4RF1G"
+                }
+            }
+        },
+        "controllers.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "contentHtml": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isDeleted": {
+                    "type": "boolean"
+                },
+                "isHidden": {
+                    "type": "boolean"
+                },
+                "parentId": {
+                    "type": "integer"
+                },
+                "postId": {
+                    "type": "integer"
+                },
+                "repliesCount": {
+                    "type": "integer"
+                },
+                "user": {
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "type": "integer"
+                        },
+                        "login": {
+                            "type": "string"
+                        },
+                        "logo": {
+                            "$ref": "#/definitions/controllers.FileResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "controllers.ComplaintResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "hide"
+                },
+                "assigneeId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "complaintType": {
+                    "type": "string",
+                    "example": "spam"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Реклама в комментариях"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "resolution": {
+                    "type": "string",
+                    "example": "Комментарий скрыт"
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                },
+                "targetId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "targetType": {
+                    "type": "string",
+                    "example": "comment"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "controllers.CreateChannelRequest": {
+            "type": "object",
+            "properties": {
+                "categoryId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "description": {
+                    "type": "string",
+                    "example": "More blogs here"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "BlogPoint News"
+                }
+            }
+        },
+        "controllers.CreateCommentRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "parentId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "postId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controllers.CreateComplaintRequest": {
+            "type": "object",
+            "properties": {
+                "complaintType": {
+                    "type": "string",
+                    "example": "spam"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Реклама в комментариях"
+                },
+                "targetId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "targetType": {
+                    "type": "string",
+                    "example": "comment"
+                }
+            }
+        },
+        "controllers.CreatePostRequest": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "content": {
+                    "type": "string",
+                    "example": "Something here"
+                },
+                "contentFormat": {
+                    "type": "string",
+                    "example": "markdown"
+                },
+                "postFiles": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "postImages": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "previewImageId": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "publishAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "draft"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Today's news"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.CategoryResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_ChannelModeratorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ChannelModeratorResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ChannelResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_IdentityResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.IdentityResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_NotificationPreferenceResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.NotificationPreferenceResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_OIDCProviderResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.OIDCProviderResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_SessionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.SessionResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_controllers_TagResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.TagResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-array_models_Role": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_ChannelModeratorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.ChannelModeratorResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.ChannelResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_ChunkedUploadResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.ChunkedUploadResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_ComplaintResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.ComplaintResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_FileResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.FileResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_PostResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.PostResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_PostRevisionDiffResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.PostRevisionDiffResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.RecoveryCodesResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_SearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.SearchResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_StatisticsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.StatisticsResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_TotpSetupResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.TotpSetupResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_UploadUrlResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.UploadUrlResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-controllers_UserResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.UserResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-int64": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DataResponse-models_Comment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.Comment"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.DiffChunk": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "example": "insert"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "New paragraph"
+                }
+            }
+        },
+        "controllers.EditChannelRequest": {
+            "type": "object",
+            "properties": {
+                "categoryId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "channelId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "More blogs here"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "BlogPoint News"
+                }
+            }
         },
-        "controllers.ChannelResponse": {
+        "controllers.EditPostRequest": {
             "type": "object",
             "properties": {
-                "category": {
-                    "$ref": "#/definitions/models.Category"
+                "content": {
+                    "type": "string",
+                    "example": "Something here"
                 },
-                "description": {
-                    "type": "string"
+                "contentFormat": {
+                    "type": "string",
+                    "example": "markdown"
                 },
-                "id": {
-                    "type": "integer"
+                "postFiles": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "logo": {
-                    "$ref": "#/definitions/controllers.FileResponse"
+                "postId": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "name": {
-                    "type": "string"
+                "postImages": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "ownerId": {
+                "previewImage": {
                     "type": "integer"
                 },
-                "subsCount": {
-                    "type": "integer"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Today's news"
                 }
             }
         },
-        "controllers.ChannelStatistics": {
+        "controllers.EditProfileRequest": {
             "type": "object",
             "properties": {
-                "comments": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "dislikes": {
-                    "type": "integer",
-                    "example": 3
-                },
-                "likes": {
-                    "type": "integer",
-                    "example": 5
-                },
-                "posts": {
-                    "type": "integer",
-                    "example": 1
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
                 },
-                "views": {
-                    "type": "integer",
-                    "example": 12
+                "login": {
+                    "type": "string",
+                    "example": "johndoe"
                 }
             }
         },
-        "controllers.CodeRequest": {
+        "controllers.EmailRequest": {
             "type": "object",
             "properties": {
-                "code": {
+                "email": {
                     "type": "string",
-                    "example": "H4RF1G"
+                    "example": "user@example.com"
                 }
             }
         },
-        "controllers.CommentResponse": {
+        "controllers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Example"
+                }
+            }
+        },
+        "controllers.FeedPostResponse": {
             "type": "object",
             "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
                 "content": {
                     "type": "string"
                 },
+                "contentFormat": {
+                    "type": "string",
+                    "example": "markdown"
+                },
+                "contentHtml": {
+                    "type": "string",
+                    "example": "\u003cp\u003eSomething \u003cstrong\u003ehere\u003c/strong\u003e\u003c/p\u003e"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "dislikesCount": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "isDeleted": {
+                "isHidden": {
                     "type": "boolean"
                 },
-                "parentId": {
+                "isSeen": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "likesCount": {
                     "type": "integer"
                 },
-                "postId": {
+                "postFiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.FileResponse"
+                    }
+                },
+                "postImages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.FileResponse"
+                    }
+                },
+                "previewImage": {
+                    "$ref": "#/definitions/controllers.FileResponse"
+                },
+                "publishAt": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "obzor-novogo-smartfona"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "viewsCount": {
                     "type": "integer"
+                }
+            }
+        },
+        "controllers.FileResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
                 },
-                "repliesCount": {
+                "id": {
                     "type": "integer"
                 },
-                "user": {
+                "url": {
+                    "type": "string"
+                },
+                "variants": {
                     "type": "object",
-                    "properties": {
-                        "id": {
-                            "type": "integer"
-                        },
-                        "login": {
-                            "type": "string"
-                        },
-                        "logo": {
-                            "$ref": "#/definitions/controllers.FileResponse"
-                        }
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "visibility": {
+                    "type": "string",
+                    "example": "public"
+                }
+            }
+        },
+        "controllers.IdentityResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "google"
+                }
+            }
+        },
+        "controllers.IdsDiff": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
                 }
             }
         },
-        "controllers.CreateChannelRequest": {
+        "controllers.LanguageUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                }
+            }
+        },
+        "controllers.LoginMfaRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "492039"
+                },
+                "mfaToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "johndoe"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret123"
+                }
+            }
+        },
+        "controllers.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Successful authorization"
+                },
+                "mfaRequired": {
+                    "type": "boolean"
+                },
+                "mfaToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.MarkFeedSeenRequest": {
+            "type": "object",
+            "properties": {
+                "postIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        15,
+                        17
+                    ]
+                }
+            }
+        },
+        "controllers.MarkNotificationsReadRequest": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "controllers.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ModerationLogResponse": {
             "type": "object",
             "properties": {
-                "categoryId": {
+                "action": {
+                    "type": "string",
+                    "example": "delete_comment"
+                },
+                "actorId": {
                     "type": "integer",
-                    "example": 12
+                    "example": 5
                 },
-                "description": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
                     "type": "string",
-                    "example": "More blogs here"
+                    "example": "Post 7"
                 },
-                "name": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "targetId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "targetType": {
                     "type": "string",
-                    "example": "BlogPoint News"
+                    "example": "comment"
                 }
             }
         },
-        "controllers.CreateCommentRequest": {
+        "controllers.NotificationPreferenceResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string",
-                    "example": "1"
+                "email": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "parentId": {
-                    "type": "integer",
-                    "example": 1
+                "inApp": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "postId": {
-                    "type": "integer",
-                    "example": 1
+                "type": {
+                    "type": "string",
+                    "example": "comment_reply"
                 }
             }
         },
-        "controllers.CreatePostRequest": {
+        "controllers.NotificationResponse": {
             "type": "object",
             "properties": {
+                "actorId": {
+                    "type": "integer",
+                    "example": 5
+                },
                 "channelId": {
                     "type": "integer",
-                    "example": 1
+                    "example": 2
                 },
-                "content": {
-                    "type": "string",
-                    "example": "Something here"
+                "commentId": {
+                    "type": "integer",
+                    "example": 42
                 },
-                "postFiles": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "createdAt": {
+                    "type": "string"
                 },
-                "postImages": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "data": {
+                    "$ref": "#/definitions/models.NotificationData"
                 },
-                "previewImageId": {
+                "id": {
                     "type": "integer",
-                    "example": 5
+                    "example": 1
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "isRead": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "title": {
+                "postId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "type": {
                     "type": "string",
-                    "example": "Today's news"
+                    "example": "comment_reply"
                 }
             }
         },
-        "controllers.DataResponse-array_controllers_CategoryResponse": {
+        "controllers.OIDCProviderResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.CategoryResponse"
-                    }
+                "displayName": {
+                    "type": "string",
+                    "example": "Google"
                 },
-                "message": {
-                    "type": "string"
+                "loginUrl": {
+                    "type": "string",
+                    "example": "/api/oidc/google/login"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "google"
                 }
             }
         },
-        "controllers.DataResponse-array_controllers_ChannelResponse": {
+        "controllers.PageResponse-array_controllers_ChannelResponse": {
             "type": "object",
             "properties": {
                 "data": {
@@ -2274,12 +6264,20 @@ const docTemplate = `{
                         "$ref": "#/definitions/controllers.ChannelResponse"
                     }
                 },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-array_controllers_CommentResponse": {
+        "controllers.PageResponse-array_controllers_CommentResponse": {
             "type": "object",
             "properties": {
                 "data": {
@@ -2288,221 +6286,373 @@ const docTemplate = `{
                         "$ref": "#/definitions/controllers.CommentResponse"
                     }
                 },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-array_controllers_PostResponse": {
+        "controllers.PageResponse-array_controllers_ComplaintResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.PostResponse"
+                        "$ref": "#/definitions/controllers.ComplaintResponse"
                     }
                 },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-array_controllers_TagResponse": {
+        "controllers.PageResponse-array_controllers_FeedPostResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.TagResponse"
+                        "$ref": "#/definitions/controllers.FeedPostResponse"
                     }
                 },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-controllers_ChannelResponse": {
+        "controllers.PageResponse-array_controllers_ModerationLogResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/controllers.ChannelResponse"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ModerationLogResponse"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-controllers_FileResponse": {
+        "controllers.PageResponse-array_controllers_NotificationResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/controllers.FileResponse"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.NotificationResponse"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-controllers_PostResponse": {
+        "controllers.PageResponse-array_controllers_PostResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/controllers.PostResponse"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PostResponse"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-controllers_StatisticsResponse": {
+        "controllers.PageResponse-array_controllers_PostRevisionResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/controllers.StatisticsResponse"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PostRevisionResponse"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "message": {
                     "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"
                 }
             }
         },
-        "controllers.DataResponse-controllers_UserResponse": {
+        "controllers.PostResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/controllers.UserResponse"
+                "channelId": {
+                    "type": "integer"
                 },
-                "message": {
+                "content": {
                     "type": "string"
-                }
-            }
-        },
-        "controllers.DataResponse-models_Comment": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.Comment"
                 },
-                "message": {
+                "contentFormat": {
+                    "type": "string",
+                    "example": "markdown"
+                },
+                "contentHtml": {
+                    "type": "string",
+                    "example": "\u003cp\u003eSomething \u003cstrong\u003ehere\u003c/strong\u003e\u003c/p\u003e"
+                },
+                "createdAt": {
                     "type": "string"
-                }
-            }
-        },
-        "controllers.EditChannelRequest": {
-            "type": "object",
-            "properties": {
-                "categoryId": {
-                    "type": "integer",
-                    "example": 12
                 },
-                "channelId": {
-                    "type": "integer",
-                    "example": 1
+                "dislikesCount": {
+                    "type": "integer"
                 },
-                "description": {
+                "id": {
+                    "type": "integer"
+                },
+                "isHidden": {
+                    "type": "boolean"
+                },
+                "likesCount": {
+                    "type": "integer"
+                },
+                "postFiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.FileResponse"
+                    }
+                },
+                "postImages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.FileResponse"
+                    }
+                },
+                "previewImage": {
+                    "$ref": "#/definitions/controllers.FileResponse"
+                },
+                "publishAt": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "slug": {
                     "type": "string",
-                    "example": "More blogs here"
+                    "example": "obzor-novogo-smartfona"
                 },
-                "name": {
+                "status": {
                     "type": "string",
-                    "example": "BlogPoint News"
+                    "example": "published"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "viewsCount": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.EditPostRequest": {
+        "controllers.PostRevisionDiffResponse": {
             "type": "object",
             "properties": {
                 "content": {
-                    "type": "string",
-                    "example": "Something here"
-                },
-                "postFiles": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/controllers.DiffChunk"
                     }
                 },
-                "postId": {
+                "fromId": {
                     "type": "integer",
-                    "example": 1
+                    "example": 2
+                },
+                "postFiles": {
+                    "$ref": "#/definitions/controllers.IdsDiff"
                 },
                 "postImages": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                    "$ref": "#/definitions/controllers.IdsDiff"
                 },
-                "previewImage": {
-                    "type": "integer"
+                "previewImageId": {
+                    "$ref": "#/definitions/controllers.IdsDiff"
                 },
                 "tags": {
+                    "$ref": "#/definitions/controllers.IdsDiff"
+                },
+                "title": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/controllers.DiffChunk"
                     }
                 },
-                "title": {
-                    "type": "string",
-                    "example": "Today's news"
+                "toId": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "controllers.EditProfileRequest": {
+        "controllers.PostRevisionResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "authorId": {
+                    "type": "integer",
+                    "example": 5
                 },
-                "login": {
+                "authorLogin": {
                     "type": "string",
                     "example": "johndoe"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "postId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.PostSnapshot"
                 }
             }
         },
-        "controllers.EmailRequest": {
+        "controllers.PostSearchResult": {
             "type": "object",
             "properties": {
-                "email": {
+                "post": {
+                    "$ref": "#/definitions/controllers.PostResponse"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.42
+                },
+                "snippet": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "… настройка \u003cmark\u003eсервера\u003c/mark\u003e на Go …"
                 }
             }
         },
-        "controllers.ErrorResponse": {
+        "controllers.RecoveryCodesResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Example"
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "7KQ2-MX9D",
+                        "P4TR-8WZN"
+                    ]
                 }
             }
         },
-        "controllers.FileResponse": {
+        "controllers.RegisterRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
                 },
-                "url": {
-                    "type": "string"
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "johndoe"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret123"
                 }
             }
         },
-        "controllers.LanguageUpdateRequest": {
+        "controllers.RequestUploadRequest": {
             "type": "object",
             "properties": {
-                "language": {
+                "filename": {
                     "type": "string",
-                    "example": "ru"
+                    "example": "lecture.mp4"
+                },
+                "mimeType": {
+                    "type": "string",
+                    "example": "video/mp4"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 734003200
+                },
+                "type": {
+                    "type": "string",
+                    "example": "video"
+                },
+                "visibility": {
+                    "type": "string",
+                    "example": "private"
                 }
             }
         },
-        "controllers.LoginRequest": {
+        "controllers.ResetPasswordRequest": {
             "type": "object",
             "properties": {
-                "login": {
+                "code": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "q8Zt0v3kR1x2cN5mW7yB9dE4fH6jL8pQ0sT2uV4wX6z"
                 },
                 "password": {
                     "type": "string",
@@ -2510,95 +6660,114 @@ const docTemplate = `{
                 }
             }
         },
-        "controllers.MessageResponse": {
+        "controllers.ResolveComplaintRequest": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "action": {
+                    "type": "string",
+                    "example": "hide"
+                },
+                "complaintId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "resolution": {
+                    "type": "string",
+                    "example": "Комментарий скрыт"
                 }
             }
         },
-        "controllers.PostResponse": {
+        "controllers.RestorePostRevisionRequest": {
             "type": "object",
             "properties": {
-                "channelId": {
-                    "type": "integer"
-                },
-                "content": {
-                    "type": "string"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "dislikesCount": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "likesCount": {
-                    "type": "integer"
+                "postId": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "postFiles": {
+                "revisionId": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "controllers.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "channels": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.FileResponse"
+                        "$ref": "#/definitions/controllers.ChannelSearchResult"
                     }
                 },
-                "postImages": {
+                "posts": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.FileResponse"
+                        "$ref": "#/definitions/controllers.PostSearchResult"
                     }
+                }
+            }
+        },
+        "controllers.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
                 },
-                "previewImage": {
-                    "$ref": "#/definitions/controllers.FileResponse"
+                "id": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Tag"
-                    }
+                "ip": {
+                    "type": "string",
+                    "example": "192.168.0.10"
                 },
-                "title": {
+                "isCurrent": {
+                    "type": "boolean"
+                },
+                "lastSeenAt": {
                     "type": "string"
                 },
-                "viewsCount": {
-                    "type": "integer"
+                "userAgent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64)"
                 }
             }
         },
-        "controllers.RegisterRequest": {
+        "controllers.SetFileVisibilityRequest": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
-                },
-                "language": {
-                    "type": "string",
-                    "example": "ru"
-                },
-                "login": {
+                "visibility": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "private"
+                }
+            }
+        },
+        "controllers.SetPostHiddenRequest": {
+            "type": "object",
+            "properties": {
+                "hidden": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "password": {
-                    "type": "string",
-                    "example": "secret123"
+                "postId": {
+                    "type": "integer",
+                    "example": 7
                 }
             }
         },
-        "controllers.ResetPasswordRequest": {
+        "controllers.SetPostStatusRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string",
-                    "example": "H4RF1G"
+                "postId": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "password": {
+                "publishAt": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string",
-                    "example": "secret123"
+                    "example": "scheduled"
                 }
             }
         },
@@ -2615,6 +6784,19 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.SetUserRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "moderator"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
         "controllers.StatisticsResponse": {
             "type": "object",
             "properties": {
@@ -2647,6 +6829,61 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.TotpSetupResponse": {
+            "type": "object",
+            "properties": {
+                "qrCode": {
+                    "description": "QrCode — PNG с URI в виде data URL",
+                    "type": "string",
+                    "example": "data:image/png;base64,iVBORw0KGgo..."
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                },
+                "uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/Blog%20Point:johndoe?algorithm=SHA1\u0026digits=6\u0026issuer=Blog%20Point\u0026period=30\u0026secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "controllers.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.NotificationPreferenceResponse"
+                    }
+                }
+            }
+        },
+        "controllers.UploadUrlResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string",
+                    "example": "PUT"
+                },
+                "uploadId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://files.blogpoint.example.com/blogpoint-bucket/video/3f2b6c1e-0d1a-4c47-9a57-1f0e9b1d2c3a.mp4?X-Amz-Algorithm=AWS4-HMAC-SHA256\u0026..."
+                }
+            }
+        },
         "controllers.UserResponse": {
             "type": "object",
             "properties": {
@@ -2667,6 +6904,14 @@ const docTemplate = `{
                 },
                 "logo": {
                     "$ref": "#/definitions/controllers.FileResponse"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "twoFactor": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
@@ -2699,6 +6944,9 @@ const docTemplate = `{
                 "isDeleted": {
                     "type": "boolean"
                 },
+                "isHidden": {
+                    "type": "boolean"
+                },
                 "parentId": {
                     "type": "integer"
                 },
@@ -2710,6 +6958,72 @@ const docTemplate = `{
                 }
             }
         },
+        "models.NotificationData": {
+            "type": "object",
+            "properties": {
+                "actorLogin": {
+                    "type": "string"
+                },
+                "channelName": {
+                    "type": "string"
+                },
+                "postTitle": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PostSnapshot": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "contentFormat": {
+                    "type": "string"
+                },
+                "postFiles": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "postImages": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "previewImageId": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Tag": {
             "type": "object",
             "properties": {
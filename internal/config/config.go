@@ -0,0 +1,476 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDev  = "dev"
+	EnvTest = "test"
+	EnvProd = "prod"
+)
+
+const redacted = "******"
+
+type Config struct {
+	Env      string         `yaml:"env" toml:"env"`
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Minio    MinioConfig    `yaml:"minio" toml:"minio"`
+	Mail     MailConfig     `yaml:"mail" toml:"mail"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Media    MediaConfig    `yaml:"media" toml:"media"`
+}
+
+type ServerConfig struct {
+	Port        string   `yaml:"port" toml:"port"`
+	BodyLimitMB int      `yaml:"bodyLimitMB" toml:"bodyLimitMB"`
+	CorsOrigins []string `yaml:"corsOrigins" toml:"corsOrigins"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`
+	SSLMode  string `yaml:"sslMode" toml:"sslMode"`
+}
+
+type MinioConfig struct {
+	InternalEndpoint string `yaml:"internalEndpoint" toml:"internalEndpoint"`
+	PublicEndpoint   string `yaml:"publicEndpoint" toml:"publicEndpoint"`
+	AccessKey        string `yaml:"accessKey" toml:"accessKey"`
+	SecretKey        string `yaml:"secretKey" toml:"secretKey"`
+	Bucket           string `yaml:"bucket" toml:"bucket"`
+	UseSSL           bool   `yaml:"useSSL" toml:"useSSL"`
+	// Region нужен для подписи ссылок без запроса к хранилищу
+	Region string `yaml:"region" toml:"region"`
+	// DirectUploadMaxMB — максимальный размер файла при загрузке напрямую в хранилище по подписанной ссылке
+	DirectUploadMaxMB int `yaml:"directUploadMaxMB" toml:"directUploadMaxMB"`
+}
+
+// Способы доставки почты
+const (
+	MailTransportSMTP = "smtp"
+	MailTransportFile = "file"
+	MailTransportNoop = "noop"
+)
+
+// Режимы шифрования SMTP: STARTTLS после подключения, TLS с самого начала или без обязательного шифрования
+const (
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLSImplicit = "tls"
+	SMTPTLSNone     = "none"
+)
+
+type MailConfig struct {
+	Transport      string     `yaml:"transport" toml:"transport"`
+	SenderName     string     `yaml:"senderName" toml:"senderName"`
+	SenderAddress  string     `yaml:"senderAddress" toml:"senderAddress"`
+	SenderPassword string     `yaml:"senderPassword" toml:"senderPassword"`
+	SMTP           SMTPConfig `yaml:"smtp" toml:"smtp"`
+	FileDir        string     `yaml:"fileDir" toml:"fileDir"`
+	// SiteURL — адрес фронтенда, на который ведут ссылки в письмах
+	SiteURL string `yaml:"siteURL" toml:"siteURL"`
+}
+
+type SMTPConfig struct {
+	Host string `yaml:"host" toml:"host"`
+	Port int    `yaml:"port" toml:"port"`
+	TLS  string `yaml:"tls" toml:"tls"`
+	// Username по умолчанию совпадает с адресом отправителя, паролем служит senderPassword
+	Username string `yaml:"username" toml:"username"`
+}
+
+type AuthConfig struct {
+	JWTSecret       string        `yaml:"jwtSecret" toml:"jwtSecret"`
+	AccessTokenTTL  time.Duration `yaml:"accessTokenTTL" toml:"accessTokenTTL"`
+	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL" toml:"refreshTokenTTL"`
+	SecureCookies   bool          `yaml:"secureCookies" toml:"secureCookies"`
+	OIDC            OIDCConfig    `yaml:"oidc" toml:"oidc"`
+}
+
+// OIDCConfig — вход через внешних провайдеров OpenID Connect
+type OIDCConfig struct {
+	// FrontendURL — куда вернуть браузер после входа у провайдера
+	FrontendURL string               `yaml:"frontendURL" toml:"frontendURL"`
+	Providers   []OIDCProviderConfig `yaml:"providers" toml:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	// Name — идентификатор провайдера в адресах /api/oidc/{name}/...
+	Name         string `yaml:"name" toml:"name"`
+	DisplayName  string `yaml:"displayName" toml:"displayName"`
+	Issuer       string `yaml:"issuer" toml:"issuer"`
+	ClientId     string `yaml:"clientId" toml:"clientId"`
+	ClientSecret string `yaml:"clientSecret" toml:"clientSecret"`
+	// RedirectURL — адрес /api/oidc/{name}/callback этого сервера, зарегистрированный у провайдера
+	RedirectURL string   `yaml:"redirectURL" toml:"redirectURL"`
+	Scopes      []string `yaml:"scopes" toml:"scopes"`
+}
+
+// MediaConfig — обработка загружаемых изображений
+type MediaConfig struct {
+	// WebP — сохранять PNG и WebP в WebP без потерь вместо PNG. JPEG остается JPEG: без потерь фотографии стали бы больше
+	WebP bool `yaml:"webp" toml:"webp"`
+}
+
+// Load собирает конфигурацию: значения по умолчанию для окружения APP_ENV,
+// затем необязательный файл CONFIG_FILE (YAML или TOML), затем переменные окружения
+func Load() (*Config, error) {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = EnvDev
+	}
+
+	cfg, err := Defaults(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err = loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+		// Окружение из файла не должно расходиться с APP_ENV, иначе применились бы чужие значения по умолчанию
+		if cfg.Env != env {
+			return nil, fmt.Errorf("config file %s is for env %q, but APP_ENV is %q", path, cfg.Env, env)
+		}
+	}
+
+	if err = applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Defaults возвращает значения по умолчанию для окружения
+func Defaults(env string) (*Config, error) {
+	cfg := &Config{
+		Env: env,
+		Server: ServerConfig{
+			Port:        ":8000",
+			BodyLimitMB: 500,
+		},
+		Database: DatabaseConfig{
+			Host:    "localhost",
+			Port:    "5432",
+			SSLMode: "disable",
+		},
+		Minio: MinioConfig{
+			Bucket:            "blogpoint-bucket",
+			Region:            "us-east-1",
+			DirectUploadMaxMB: 2048,
+		},
+		Mail: MailConfig{
+			Transport:  MailTransportSMTP,
+			SenderName: "BlogPoint",
+			SMTP: SMTPConfig{
+				Host: "smtp.gmail.com",
+				Port: 587,
+				TLS:  SMTPTLSStartTLS,
+			},
+			FileDir: "mail",
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+	}
+
+	switch env {
+	case EnvDev:
+		cfg.Server.CorsOrigins = []string{"http://localhost:5173"}
+		cfg.Mail.SiteURL = "http://localhost:5173"
+		cfg.Auth.JWTSecret = "dev-only-secret-do-not-use-in-production"
+	case EnvTest:
+		cfg.Server.BodyLimitMB = 10
+		cfg.Server.CorsOrigins = []string{"http://localhost:5173"}
+		cfg.Database.Name = "test"
+		cfg.Mail.Transport = MailTransportFile
+		cfg.Mail.SiteURL = "http://localhost:5173"
+		cfg.Auth.JWTSecret = "test-only-secret-do-not-use-in-production"
+	case EnvProd:
+		cfg.Database.SSLMode = "require"
+		cfg.Minio.UseSSL = true
+		cfg.Mail.SiteURL = "https://blogpoint.com"
+		cfg.Auth.SecureCookies = true
+	default:
+		return nil, fmt.Errorf("unknown env %q, expected one of %s, %s, %s", env, EnvDev, EnvTest, EnvProd)
+	}
+
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, cfg)
+	case ".toml":
+		err = toml.Unmarshal(content, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func applyEnv(cfg *Config) error {
+	setString(&cfg.Server.Port, "SERVER_PORT")
+	if origins := os.Getenv("CORS_ORIGINS"); origins != "" {
+		cfg.Server.CorsOrigins = strings.Split(origins, ",")
+	}
+	if err := setInt(&cfg.Server.BodyLimitMB, "BODY_LIMIT_MB"); err != nil {
+		return err
+	}
+
+	setString(&cfg.Database.Host, "DB_HOST")
+	setString(&cfg.Database.Port, "DB_PORT")
+	setString(&cfg.Database.User, "DB_USER")
+	setString(&cfg.Database.Password, "DB_PASSWORD")
+	setString(&cfg.Database.Name, "DB_NAME")
+	setString(&cfg.Database.SSLMode, "DB_SSL_MODE")
+
+	setString(&cfg.Minio.InternalEndpoint, "MINIO_INTERNAL_ENDPOINT")
+	setString(&cfg.Minio.PublicEndpoint, "MINIO_PUBLIC_ENDPOINT")
+	setString(&cfg.Minio.AccessKey, "MINIO_ACCESS_KEY")
+	setString(&cfg.Minio.SecretKey, "MINIO_SECRET_KEY")
+	setString(&cfg.Minio.Bucket, "MINIO_BUCKET")
+	if err := setBool(&cfg.Minio.UseSSL, "MINIO_USE_SSL"); err != nil {
+		return err
+	}
+	setString(&cfg.Minio.Region, "MINIO_REGION")
+	if err := setInt(&cfg.Minio.DirectUploadMaxMB, "MINIO_DIRECT_UPLOAD_MAX_MB"); err != nil {
+		return err
+	}
+
+	setString(&cfg.Mail.SenderName, "EMAIL_SENDER_NAME")
+	setString(&cfg.Mail.SenderAddress, "EMAIL_SENDER_ADDRESS")
+	setString(&cfg.Mail.SenderPassword, "EMAIL_SENDER_PASSWORD")
+	setString(&cfg.Mail.Transport, "MAIL_TRANSPORT")
+	setString(&cfg.Mail.SMTP.Host, "SMTP_HOST")
+	if err := setInt(&cfg.Mail.SMTP.Port, "SMTP_PORT"); err != nil {
+		return err
+	}
+	setString(&cfg.Mail.SMTP.TLS, "SMTP_TLS")
+	setString(&cfg.Mail.SMTP.Username, "SMTP_USERNAME")
+	setString(&cfg.Mail.FileDir, "MAIL_FILE_DIR")
+	setString(&cfg.Mail.SiteURL, "SITE_URL")
+
+	setString(&cfg.Auth.JWTSecret, "JWT_SECRET")
+	if err := setDuration(&cfg.Auth.AccessTokenTTL, "ACCESS_TOKEN_TTL"); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.Auth.RefreshTokenTTL, "REFRESH_TOKEN_TTL"); err != nil {
+		return err
+	}
+	if err := setBool(&cfg.Auth.SecureCookies, "SECURE_COOKIES"); err != nil {
+		return err
+	}
+
+	setString(&cfg.Auth.OIDC.FrontendURL, "OIDC_FRONTEND_URL")
+	// Секреты провайдеров из файла можно передать через OIDC_<NAME>_CLIENT_SECRET
+	for i := range cfg.Auth.OIDC.Providers {
+		provider := &cfg.Auth.OIDC.Providers[i]
+		name := strings.ToUpper(strings.ReplaceAll(provider.Name, "-", "_"))
+		setString(&provider.ClientSecret, "OIDC_"+name+"_CLIENT_SECRET")
+	}
+
+	if err := setBool(&cfg.Media.WebP, "MEDIA_WEBP"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate проверяет, что конфигурация пригодна для запуска
+func (cfg *Config) Validate() error {
+	var errs []error
+
+	if cfg.Server.Port == "" {
+		errs = append(errs, errors.New("server.port is required"))
+	}
+	if cfg.Server.BodyLimitMB <= 0 {
+		errs = append(errs, errors.New("server.bodyLimitMB must be positive"))
+	}
+	if len(cfg.Server.CorsOrigins) == 0 {
+		errs = append(errs, errors.New("server.corsOrigins is required"))
+	}
+
+	if cfg.Database.Host == "" || cfg.Database.Port == "" || cfg.Database.User == "" || cfg.Database.Name == "" {
+		errs = append(errs, errors.New("database host, port, user and name are required"))
+	}
+
+	if cfg.Minio.InternalEndpoint == "" || cfg.Minio.PublicEndpoint == "" {
+		errs = append(errs, errors.New("minio internal and public endpoints are required"))
+	}
+	if cfg.Minio.AccessKey == "" || cfg.Minio.SecretKey == "" {
+		errs = append(errs, errors.New("minio access and secret keys are required"))
+	}
+	if cfg.Minio.Bucket == "" {
+		errs = append(errs, errors.New("minio.bucket is required"))
+	}
+	if cfg.Minio.DirectUploadMaxMB <= 0 {
+		errs = append(errs, errors.New("minio.directUploadMaxMB must be positive"))
+	}
+
+	if cfg.Mail.SenderAddress == "" {
+		errs = append(errs, errors.New("mail.senderAddress is required"))
+	}
+	if siteURL, err := url.Parse(cfg.Mail.SiteURL); err != nil || siteURL.Host == "" ||
+		(siteURL.Scheme != "http" && siteURL.Scheme != "https") {
+		errs = append(errs, errors.New("mail.siteURL must be an absolute http(s) URL"))
+	}
+	switch cfg.Mail.Transport {
+	case MailTransportSMTP:
+		if cfg.Mail.SMTP.Host == "" || cfg.Mail.SMTP.Port <= 0 {
+			errs = append(errs, errors.New("mail.smtp host and port are required"))
+		}
+		if cfg.Mail.SMTP.TLS != SMTPTLSStartTLS && cfg.Mail.SMTP.TLS != SMTPTLSImplicit && cfg.Mail.SMTP.TLS != SMTPTLSNone {
+			errs = append(errs, fmt.Errorf("mail.smtp.tls must be one of %s, %s, %s",
+				SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone))
+		}
+	case MailTransportFile:
+		if cfg.Mail.FileDir == "" {
+			errs = append(errs, errors.New("mail.fileDir is required for the file transport"))
+		}
+	case MailTransportNoop:
+	default:
+		errs = append(errs, fmt.Errorf("mail.transport must be one of %s, %s, %s",
+			MailTransportSMTP, MailTransportFile, MailTransportNoop))
+	}
+
+	if cfg.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("auth.jwtSecret is required"))
+	} else if cfg.Env == EnvProd && len(cfg.Auth.JWTSecret) < 32 {
+		errs = append(errs, errors.New("auth.jwtSecret must be at least 32 characters in prod"))
+	}
+	if cfg.Auth.AccessTokenTTL <= 0 || cfg.Auth.RefreshTokenTTL <= 0 {
+		errs = append(errs, errors.New("auth token TTLs must be positive"))
+	}
+	if cfg.Auth.AccessTokenTTL >= cfg.Auth.RefreshTokenTTL {
+		errs = append(errs, errors.New("auth.accessTokenTTL must be shorter than auth.refreshTokenTTL"))
+	}
+
+	if len(cfg.Auth.OIDC.Providers) > 0 && cfg.Auth.OIDC.FrontendURL == "" {
+		errs = append(errs, errors.New("auth.oidc.frontendURL is required when OIDC providers are configured"))
+	}
+	names := make(map[string]bool, len(cfg.Auth.OIDC.Providers))
+	for _, provider := range cfg.Auth.OIDC.Providers {
+		if provider.Name == "" || provider.Issuer == "" || provider.ClientId == "" || provider.RedirectURL == "" {
+			errs = append(errs, errors.New("auth.oidc providers require name, issuer, clientId and redirectURL"))
+			continue
+		}
+		if names[provider.Name] {
+			errs = append(errs, fmt.Errorf("auth.oidc provider %q is defined twice", provider.Name))
+		}
+		names[provider.Name] = true
+		// Локальный issuer по http допустим для разработки и тестов, в prod — только https
+		if cfg.Env == EnvProd && !strings.HasPrefix(provider.Issuer, "https://") {
+			errs = append(errs, fmt.Errorf("auth.oidc provider %q issuer must use https in prod", provider.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted возвращает копию конфигурации, безопасную для вывода в логи
+func (cfg Config) Redacted() Config {
+	cfg.Server.CorsOrigins = append([]string(nil), cfg.Server.CorsOrigins...)
+	cfg.Database.Password = redact(cfg.Database.Password)
+	cfg.Minio.SecretKey = redact(cfg.Minio.SecretKey)
+	cfg.Mail.SenderPassword = redact(cfg.Mail.SenderPassword)
+	cfg.Auth.JWTSecret = redact(cfg.Auth.JWTSecret)
+	cfg.Auth.OIDC.Providers = append([]OIDCProviderConfig(nil), cfg.Auth.OIDC.Providers...)
+	for i := range cfg.Auth.OIDC.Providers {
+		cfg.Auth.OIDC.Providers[i].ClientSecret = redact(cfg.Auth.OIDC.Providers[i].ClientSecret)
+	}
+	return cfg
+}
+
+func (cfg Config) String() string {
+	// Псевдоним без метода String, чтобы fmt не вызвал его рекурсивно
+	type plain Config
+	return fmt.Sprintf("%+v", plain(cfg.Redacted()))
+}
+
+// DSN формирует строку подключения к PostgreSQL
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+func setString(target *string, key string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*target = value
+	}
+}
+
+func setInt(target *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func setBool(target *bool, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func setDuration(target *time.Duration, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*target = parsed
+	return nil
+}
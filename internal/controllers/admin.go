@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"log"
+)
+
+// GetRoles возвращает список ролей платформы
+// @Summary      Получение ролей
+// @Description  Возвращает все роли платформы. Доступно только администраторам.
+// @Tags         Admin
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  DataResponse[[]models.Role]
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/admin/getRoles [get]
+func GetRoles(c *fiber.Ctx) error {
+	var roles []models.Role
+	if err := repository.DB.Order("id").Find(&roles).Error; err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to fetch roles",
+		})
+	}
+
+	return c.JSON(DataResponse[[]models.Role]{
+		Data: roles,
+	})
+}
+
+// SetUserRole назначает пользователю роль
+// @Summary      Назначение роли
+// @Description  Назначает пользователю роль user, moderator или admin. Доступно только администраторам, снять роль с себя нельзя.
+// @Tags         Admin
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      SetUserRoleRequest true "Пользователь и новая роль"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/admin/setUserRole [patch]
+func SetUserRole(c *fiber.Ctx) error {
+	var data SetUserRoleRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	admin := CurrentUser(c)
+
+	if data.UserId == 0 {
+		c.Status(fiber.StatusBadRequest)
+		return c.JSON(ErrorResponse{
+			Message: "User id is required",
+		})
+	}
+
+	// Иначе последний администратор может случайно лишить платформу управления
+	if data.UserId == admin.Id {
+		c.Status(fiber.StatusBadRequest)
+		return c.JSON(ErrorResponse{
+			Message: "You cannot change your own role",
+		})
+	}
+
+	var role models.Role
+	if err := repository.DB.Where("name = ?", data.Role).First(&role).Error; err != nil {
+		c.Status(fiber.StatusBadRequest)
+		return c.JSON(ErrorResponse{
+			Message: "Unknown role",
+		})
+	}
+
+	var user models.User
+	if err := repository.DB.First(&user, data.UserId).Error; err != nil {
+		c.Status(fiber.StatusNotFound)
+		return c.JSON(ErrorResponse{
+			Message: "User not found",
+		})
+	}
+
+	if err := repository.DB.Model(&user).Update("role_id", role.Id).Error; err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to update user role",
+		})
+	}
+
+	log.Printf("Администратор %d назначил пользователю %d роль %s", admin.Id, user.Id, role.Name)
+
+	return c.JSON(MessageResponse{
+		Message: "User role updated successfully",
+	})
+}
@@ -2,23 +2,22 @@ package controllers
 
 import (
 	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/media"
 	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/ratelimit"
 	"blogpoint-backend/internal/repository"
 	"blogpoint-backend/internal/storage"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
-	"math/rand"
+	"log"
 	"strconv"
 	"strings"
 	"time"
 )
 
-const SecretKey = "secret"
-const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-
 // Register регистрирует нового пользователя
 // @Summary      Регистрация пользователя
 // @Description  Регистрация нового пользователя
@@ -92,14 +91,15 @@ func Register(c *fiber.Ctx) error {
 
 // Login авторизует пользователя
 // @Summary      Авторизация
-// @Description  Авторизация пользователя
+// @Description  Авторизация пользователя. После 5 неверных паролей подряд аккаунт блокируется на минуту, каждая следующая ошибка удваивает блокировку (до часа). Запросы с одного IP ограничены, состояние лимита возвращается в заголовках X-RateLimit-*. Если включена двухфакторная аутентификация, cookie не выставляются, а в ответе приходит mfaToken для /api/loginMfa
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        data  body      LoginRequest true "Логин и пароль"
-// @Success      200   {object}  MessageResponse
+// @Success      200   {object}  LoginResponse
 // @Failure      400   {object}  ErrorResponse
 // @Failure      404   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
 // @Router       /api/login [post]
 func Login(c *fiber.Ctx) error {
 	var data LoginRequest
@@ -119,60 +119,237 @@ func Login(c *fiber.Ctx) error {
 		})
 	}
 
+	// Заблокированный аккаунт отклоняется до проверки пароля, чтобы перебор не тратил время на bcrypt
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return accountLocked(c, time.Until(*user.LockedUntil))
+	}
+
 	if err := bcrypt.CompareHashAndPassword(user.Password, []byte(data.Password)); err != nil {
+		lock, err := registerFailedLogin(&user)
+		if err != nil {
+			log.Printf("Не удалось учесть неудачный вход пользователя %d: %v", user.Id, err)
+		}
+		if lock > 0 {
+			log.Printf("Аккаунт %d заблокирован на %s после неудачных попыток входа (ip %s)", user.Id, lock, c.IP())
+			return accountLocked(c, lock)
+		}
+
 		c.Status(fiber.StatusBadRequest)
 		return c.JSON(ErrorResponse{
 			Message: "Incorrect password",
 		})
 	}
 
-	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss": strconv.Itoa(int(user.Id)),
-		"exp": time.Now().Add(time.Hour).Unix(),
+	// При включенной двухфакторной аутентификации сессия открывается только после кода.
+	// Счетчик неудачных попыток не сбрасываем, иначе верный пароль позволял бы перебирать коды бесконечно.
+	if user.TotpEnabled {
+		token, err := issueMfaToken(user.Id)
+		if err != nil {
+			c.Status(fiber.StatusInternalServerError)
+			return c.JSON(ErrorResponse{
+				Message: "Could not login",
+			})
+		}
+
+		return c.JSON(LoginResponse{
+			Message:     "Two-factor authentication required",
+			MfaRequired: true,
+			MfaToken:    token,
+		})
+	}
+
+	return completeLogin(c, &user)
+}
+
+// Logout завершает сессию пользователя
+// @Summary      Выход из аккаунта
+// @Description  Отзывает серверную сессию и удаляет cookie с токенами
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  MessageResponse
+// @Router       /api/logout [post]
+func Logout(c *fiber.Ctx) error {
+	session := CurrentSession(c)
+	if session == nil {
+		session, _ = findSessionByRefreshToken(c.Cookies(refreshCookieName))
+	}
+
+	if session != nil {
+		if err := revokeSession(session); err != nil {
+			c.Status(fiber.StatusInternalServerError)
+			return c.JSON(ErrorResponse{
+				Message: "Failed to revoke session",
+			})
+		}
+	}
+
+	clearAuthCookies(c)
+
+	return c.JSON(MessageResponse{
+		Message: "Logged out successfully",
 	})
+}
+
+// Refresh обновляет пару токенов
+// @Summary      Обновление токенов
+// @Description  Выдает новый access токен и ротирует refresh токен из cookie. Повторное использование старого refresh токена отзывает сессию. Если токен только что заменил параллельный запрос (в пределах 30 секунд), возвращается 409 без отзыва сессии: новые cookie уже выданы этим запросом
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  MessageResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/refresh [post]
+func Refresh(c *fiber.Ctx) error {
+	refreshToken := c.Cookies(refreshCookieName)
+	if refreshToken == "" {
+		c.Status(fiber.StatusUnauthorized)
+		return c.JSON(ErrorResponse{
+			Message: "Refresh token is missing",
+		})
+	}
+
+	if _, err := rotateSession(c, refreshToken); err != nil {
+		switch {
+		case errors.Is(err, errRefreshTokenReuse):
+			log.Printf("⚠️ Повторное использование refresh токена, сессия отозвана (ip %s)", c.IP())
+			clearAuthCookies(c)
+			c.Status(fiber.StatusUnauthorized)
+			return c.JSON(ErrorResponse{
+				Message: "Refresh token reuse detected, session revoked",
+			})
+		case errors.Is(err, errRefreshTokenRotated):
+			// Cookie не сбрасываем, чтобы не затереть новые, выданные параллельным запросом
+			c.Status(fiber.StatusConflict)
+			return c.JSON(ErrorResponse{
+				Message: "Refresh token was already rotated",
+			})
+		case errors.Is(err, errInvalidRefreshToken):
+			clearAuthCookies(c)
+			c.Status(fiber.StatusUnauthorized)
+			return c.JSON(ErrorResponse{
+				Message: "Invalid refresh token",
+			})
+		default:
+			c.Status(fiber.StatusInternalServerError)
+			return c.JSON(ErrorResponse{
+				Message: "Failed to refresh token",
+			})
+		}
+	}
 
-	token, err := claims.SignedString([]byte(SecretKey))
+	return c.JSON(MessageResponse{
+		Message: "Token refreshed",
+	})
+}
 
-	if err != nil {
+// GetSessions возвращает активные сессии пользователя
+// @Summary      Активные сессии
+// @Description  Возвращает список активных сессий текущего пользователя (устройство, IP, время создания и последней активности)
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  DataResponse[[]SessionResponse]
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/getSessions [get]
+func GetSessions(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+	current := CurrentSession(c)
+
+	var sessions []models.Session
+	if err := repository.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", user.Id, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
 		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
-			Message: "Could not login",
+			Message: "Failed to retrieve sessions",
 		})
 	}
 
-	cookie := fiber.Cookie{
-		Name:     "jwt",
-		Value:    token,
-		Expires:  time.Now().Add(time.Hour),
-		HTTPOnly: true,
+	sessionsResponse := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		sessionsResponse = append(sessionsResponse, ConvertSessionToResponse(session, current.Id))
 	}
 
-	c.Cookie(&cookie)
+	return c.JSON(DataResponse[[]SessionResponse]{
+		Data: sessionsResponse,
+	})
+}
+
+// RevokeSession завершает одну из сессий пользователя
+// @Summary      Завершение сессии
+// @Description  Отзывает указанную сессию текущего пользователя, например на потерянном устройстве
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int true "Id сессии"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/revokeSession/{id} [delete]
+func RevokeSession(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	sessionId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || sessionId == 0 {
+		c.Status(fiber.StatusBadRequest)
+		return c.JSON(ErrorResponse{
+			Message: "Invalid session id",
+		})
+	}
+
+	var session models.Session
+	if err = repository.DB.Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionId, user.Id).
+		First(&session).Error; err != nil {
+		c.Status(fiber.StatusNotFound)
+		return c.JSON(ErrorResponse{
+			Message: "Session not found",
+		})
+	}
+
+	if err = revokeSession(&session); err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to revoke session",
+		})
+	}
+
+	if session.Id == CurrentSession(c).Id {
+		clearAuthCookies(c)
+	}
 
 	return c.JSON(MessageResponse{
-		Message: "Successful authorization",
+		Message: "Session revoked successfully",
 	})
 }
 
-// Logout завершает сессию пользователя
-// @Summary      Выход из аккаунта
-// @Description  Удаляет JWT cookie и завершает сессию
+// RevokeOtherSessions завершает все сессии пользователя, кроме текущей
+// @Summary      Выход на других устройствах
+// @Description  Отзывает все сессии текущего пользователя, кроме той, из которой выполнен запрос
 // @Tags         Auth
+// @Security     ApiKeyAuth
 // @Produce      json
 // @Success      200  {object}  MessageResponse
-// @Router       /api/logout [post]
-func Logout(c *fiber.Ctx) error {
-	cookie := fiber.Cookie{
-		Name:     "jwt",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour),
-		HTTPOnly: true,
-	}
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/revokeOtherSessions [delete]
+func RevokeOtherSessions(c *fiber.Ctx) error {
+	user := CurrentUser(c)
 
-	c.Cookie(&cookie)
+	revoked, err := revokeUserSessions(user.Id, CurrentSession(c).Id)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to revoke sessions",
+		})
+	}
 
 	return c.JSON(MessageResponse{
-		Message: "Logged out successfully",
+		Message: fmt.Sprintf("%d sessions revoked", revoked),
 	})
 }
 
@@ -186,22 +363,7 @@ func Logout(c *fiber.Ctx) error {
 // @Failure      401  {object}  ErrorResponse
 // @Router       /api/user [get]
 func User(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
-
-	var user models.User
-
-	repository.DB.Where("id = ?", claims["iss"]).First(&user)
+	user := CurrentUser(c)
 
 	var logo *models.File
 	if user.LogoId != nil {
@@ -221,7 +383,7 @@ func User(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(DataResponse[UserResponse]{
-		Data: ConvertUserToResponse(user, logo),
+		Data: ConvertUserToResponse(*user, logo),
 	})
 }
 
@@ -243,21 +405,7 @@ func EditProfile(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
-
-	var user models.User
-	repository.DB.Where("id = ?", claims["iss"]).First(&user)
+	user := CurrentUser(c)
 
 	var logo *models.File
 	if user.LogoId != nil {
@@ -283,10 +431,10 @@ func EditProfile(c *fiber.Ctx) error {
 		user.Email = data.Email
 	}
 
-	repository.DB.Save(&user)
+	repository.DB.Save(user)
 
 	return c.JSON(DataResponse[UserResponse]{
-		Data:    ConvertUserToResponse(user, logo),
+		Data:    ConvertUserToResponse(*user, logo),
 		Message: "Profile edited successfully",
 	})
 }
@@ -311,26 +459,7 @@ func ChangePassword(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
-
-	var user models.User
-	if err = repository.DB.Where("id = ?", claims["iss"]).First(&user).Error; err != nil {
-		c.Status(fiber.StatusNotFound)
-		return c.JSON(ErrorResponse{
-			Message: "User not found",
-		})
-	}
+	user := CurrentUser(c)
 
 	if data.OldPassword == "" || data.NewPassword == "" || data.OldPassword == data.NewPassword {
 		c.Status(fiber.StatusBadRequest)
@@ -339,7 +468,7 @@ func ChangePassword(c *fiber.Ctx) error {
 		})
 	}
 
-	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(data.OldPassword)); err != nil {
+	if err := bcrypt.CompareHashAndPassword(user.Password, []byte(data.OldPassword)); err != nil {
 		c.Status(fiber.StatusBadRequest)
 		return c.JSON(ErrorResponse{
 			Message: "Incorrect old password",
@@ -353,13 +482,21 @@ func ChangePassword(c *fiber.Ctx) error {
 		})
 	}
 	user.Password = password
-	if err = repository.DB.Save(&user).Error; err != nil {
+	if err = repository.DB.Save(user).Error; err != nil {
 		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
 			Message: "Failed to update password",
 		})
 	}
 
+	// Пароль мог быть скомпрометирован, поэтому выходим на всех остальных устройствах
+	if _, err = revokeUserSessions(user.Id, CurrentSession(c).Id); err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to revoke other sessions",
+		})
+	}
+
 	return c.JSON(MessageResponse{
 		Message: "Password changed successfully",
 	})
@@ -385,26 +522,7 @@ func LanguageUpdate(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
-
-	var user models.User
-	if err = repository.DB.Where("id = ?", claims["iss"]).First(&user).Error; err != nil {
-		c.Status(fiber.StatusNotFound)
-		return c.JSON(ErrorResponse{
-			Message: "User not found",
-		})
-	}
+	user := CurrentUser(c)
 
 	if user.Language == data.Language {
 		return c.JSON(MessageResponse{
@@ -420,7 +538,7 @@ func LanguageUpdate(c *fiber.Ctx) error {
 	}
 
 	user.Language = data.Language
-	if err = repository.DB.Save(&user).Error; err != nil {
+	if err := repository.DB.Save(user).Error; err != nil {
 		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
 			Message: "Failed to update language",
@@ -434,7 +552,7 @@ func LanguageUpdate(c *fiber.Ctx) error {
 
 // UploadUserLogo загружает новое лого пользователя.
 // @Summary      Загрузка лого пользователя
-// @Description  Загружает изображение и устанавливает его как лого текущего авторизованного пользователя.
+// @Description  Загружает изображение и устанавливает его как лого текущего авторизованного пользователя. Создаются квадратные варианты avatar_64, avatar_128 и avatar_256
 // @Tags         User
 // @Security     ApiKeyAuth
 // @Accept       multipart/form-data
@@ -443,47 +561,18 @@ func LanguageUpdate(c *fiber.Ctx) error {
 // @Success      200   {object}  DataResponse[FileResponse]
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
+// @Failure      413   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /api/uploadUserLogo [post]
 func UploadUserLogo(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
-
-	var user models.User
-	if err = repository.DB.First(&user, userId).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Message: "User not found"})
-	}
+	user := CurrentUser(c)
 
 	var oldLogoId uint
 	if user.LogoId != nil {
 		oldLogoId = *user.LogoId
 	}
 
-	filename, mimeType, err := ProcessUpload(c, "image")
+	file, err := ProcessUpload(c, "image", media.PresetAvatar, models.FileVisibilityPublic)
 
 	if err != nil {
 		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
@@ -491,17 +580,13 @@ func UploadUserLogo(c *fiber.Ctx) error {
 		})
 	}
 
-	file := models.File{
-		OwnerId:  uint(userId),
-		Filename: filename,
-		MimeType: mimeType,
-	}
+	file.OwnerId = user.Id
 
 	if err = repository.DB.Create(&file).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving file to DB"})
 	}
 
-	if err = repository.DB.Model(&user).Update("logo_id", file.Id).Error; err != nil {
+	if err = repository.DB.Model(user).Update("logo_id", file.Id).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving logo id"})
 	}
 
@@ -513,7 +598,7 @@ func UploadUserLogo(c *fiber.Ctx) error {
 			})
 		}
 
-		if err = storage.DeleteFromMinIO(c.Context(), oldFile.Filename); err != nil {
+		if err = storage.DeleteFromMinIO(c.Context(), oldFile.Filename, oldFile.VariantObjects()...); err != nil {
 			return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
 				Message: err.(*fiber.Error).Error(),
 			})
@@ -526,15 +611,8 @@ func UploadUserLogo(c *fiber.Ctx) error {
 		}
 	}
 
-	url := storage.GetUrl(filename)
-
-	fileResponse := FileResponse{
-		Id:  file.Id,
-		Url: url,
-	}
-
 	return c.JSON(DataResponse[FileResponse]{
-		Data:    fileResponse,
+		Data:    newFileResponse(file),
 		Message: "User logo uploaded successfully",
 	})
 }
@@ -552,39 +630,7 @@ func UploadUserLogo(c *fiber.Ctx) error {
 // @Failure      500 {object} ErrorResponse
 // @Router       /api/deleteUserLogo [delete]
 func DeleteUserLogo(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
-
-	var user models.User
-	if err = repository.DB.First(&user, "id = ?", userId).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
-			Message: "User not found",
-		})
-	}
+	user := CurrentUser(c)
 
 	if user.LogoId == nil {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
@@ -593,25 +639,25 @@ func DeleteUserLogo(c *fiber.Ctx) error {
 	}
 
 	var file models.File
-	if err = repository.DB.First(&file, "id = ?", user.LogoId).Error; err != nil {
+	if err := repository.DB.First(&file, "id = ?", user.LogoId).Error; err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
 			Message: "File not found",
 		})
 	}
 
-	if err = storage.DeleteFromMinIO(c.Context(), file.Filename); err != nil {
+	if err := storage.DeleteFromMinIO(c.Context(), file.Filename, file.VariantObjects()...); err != nil {
 		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
 			Message: err.(*fiber.Error).Error(),
 		})
 	}
 
-	if err = repository.DB.Delete(&file).Error; err != nil {
+	if err := repository.DB.Delete(&file).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Message: "Error deleting file from database",
 		})
 	}
 
-	if err = repository.DB.Model(&user).Update("logo_id", nil).Error; err != nil {
+	if err := repository.DB.Model(user).Update("logo_id", nil).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Message: "Error clearing user logo",
 		})
@@ -622,61 +668,35 @@ func DeleteUserLogo(c *fiber.Ctx) error {
 	})
 }
 
-func generateCode() string {
-	rand.Seed(time.Now().UnixNano())
-	code := make([]byte, 6)
-	for i := range code {
-		code[i] = charset[rand.Intn(len(charset))]
-	}
-	return string(code)
-}
-
 // RequestEmailVerification отправляет код подтверждения на email
 // @Summary      Запрос кода подтверждения email
-// @Description  Отправление кода на почту для её подтверждения
+// @Description  Отправление кода на почту для её подтверждения. Число писем на аккаунт ограничено
 // @Tags         User
 // @Security     ApiKeyAuth
 // @Produce      json
 // @Success      200 {object}  MessageResponse
 // @Failure      401 {object}  ErrorResponse
+// @Failure      429 {object}  ErrorResponse
 // @Router       /api/requestEmailVerification [post]
-func RequestEmailVerification(c *fiber.Ctx, emailSender mail.EmailSender) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
+func RequestEmailVerification(c *fiber.Ctx, emailSender mail.EmailSender, limiter *ratelimit.Limiter) error {
+	user := CurrentUser(c)
 
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	uintId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
+	if err := checkRateLimit(c, limiter, "email_verification:"+strconv.FormatUint(uint64(user.Id), 10)); err != nil {
+		return c.Status(err.Code).JSON(ErrorResponse{Message: err.Message})
 	}
 
 	// Удаляем старый код
-	repository.DB.Delete(&models.VerificationCode{}, "user_id = ? AND type = ?", uintId, "email_verification")
+	repository.DB.Delete(&models.VerificationCode{}, "user_id = ? AND type = ?", user.Id, "email_verification")
 
 	// Генерируем код
-	code := generateCode()
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
 	expiresAt := time.Now().Add(10 * time.Minute)
 
 	verification := models.VerificationCode{
-		UserId:    uint(uintId),
+		UserId:    user.Id,
 		Code:      code,
 		Type:      "email_verification",
 		ExpiresAt: expiresAt,
@@ -684,36 +704,25 @@ func RequestEmailVerification(c *fiber.Ctx, emailSender mail.EmailSender) error
 	repository.DB.Create(&verification)
 
 	// Отправляем email
-	var user models.User
-	repository.DB.First(&user, uintId)
-
-	subject := "Blog point verification code"
-	content := fmt.Sprintf(`
-    <h1>Email Verification</h1>
-    <p>Your verification code is: <strong>%s</strong></p>
-    <p>Please enter this code on the website to verify your email.</p>
-    <p>This code will expire in 10 minutes.</p>
-    <p>Best regards, <br>Blog Point Team</p>`, code)
-
-	to := []string{user.Email}
-
-	err = emailSender.SendEmail(subject, content, to, nil, nil, nil)
-
-	fmt.Println(emailSender)
-
-	fmt.Println(err)
-
+	message, err := mail.Render(mail.TemplateEmailVerification, user.Language, mail.CodeData{
+		Code:         code,
+		ValidMinutes: 10,
+	})
 	if err != nil {
 		return err
 	}
 
+	if err = emailSender.SendMessage(message, []string{user.Email}); err != nil {
+		return err
+	}
+
 	return c.JSON(MessageResponse{
 		Message: "Verification code sent"})
 }
 
 // VerifyEmail проверяет код подтверждения email
 // @Summary      Подтверждение email
-// @Description  Подтверждение email полученным кодом
+// @Description  Подтверждение email полученным кодом. После 5 неверных вводов код аннулируется
 // @Tags         User
 // @Security     ApiKeyAuth
 // @Accept       json
@@ -722,6 +731,7 @@ func RequestEmailVerification(c *fiber.Ctx, emailSender mail.EmailSender) error
 // @Success      200   {object}  MessageResponse
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
 // @Router       /api/verifyEmail [post]
 func VerifyEmail(c *fiber.Ctx) error {
 	var data CodeRequest
@@ -730,32 +740,16 @@ func VerifyEmail(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
+	user := CurrentUser(c)
 
 	// Проверяем код
-	var verification models.VerificationCode
-	err = repository.DB.Where(
-		"user_id = ? AND code = ? AND type = ? AND expires_at > ?",
-		claims["iss"], data.Code, "email_verification", time.Now(),
-	).First(&verification).Error
-
+	verification, err := checkVerificationCode(user.Id, "email_verification", data.Code)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid or expired code"})
+		return verificationCodeError(c, err)
 	}
 
 	// Подтверждаем email
-	repository.DB.Model(&models.User{}).Where("id = ?", claims["iss"]).Update("is_verified", true)
+	repository.DB.Model(&models.User{}).Where("id = ?", user.Id).Update("is_verified", true)
 
 	// Удаляем код
 	repository.DB.Delete(&verification)
@@ -766,7 +760,7 @@ func VerifyEmail(c *fiber.Ctx) error {
 
 // RequestPasswordReset отправляет ссылку для сброса пароля
 // @Summary      Запрос на сброс пароля
-// @Description  Отправление ссылки для сброса пароля на почту пользователя
+// @Description  Отправление ссылки для сброса пароля на почту пользователя. Число писем на один email ограничено
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -774,8 +768,9 @@ func VerifyEmail(c *fiber.Ctx) error {
 // @Success      200   {object}  MessageResponse
 // @Failure      400   {object}  ErrorResponse
 // @Failure      404   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
 // @Router       /api/requestPasswordReset [post]
-func RequestPasswordReset(c *fiber.Ctx, emailSender mail.EmailSender) error {
+func RequestPasswordReset(c *fiber.Ctx, emailSender mail.EmailSender, limiter *ratelimit.Limiter) error {
 	var data EmailRequest
 	if err := json.Unmarshal(c.Body(), &data); err != nil {
 		return err
@@ -786,6 +781,10 @@ func RequestPasswordReset(c *fiber.Ctx, emailSender mail.EmailSender) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Email is required"})
 	}
 
+	if err := checkRateLimit(c, limiter, "password_reset:"+strings.ToLower(email)); err != nil {
+		return c.Status(err.Code).JSON(ErrorResponse{Message: err.Message})
+	}
+
 	var user models.User
 	if err := repository.DB.Where("email = ?", email).First(&user).Error; err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"message": "Email not found"})
@@ -793,37 +792,37 @@ func RequestPasswordReset(c *fiber.Ctx, emailSender mail.EmailSender) error {
 
 	repository.DB.Delete(&models.VerificationCode{}, "user_id = ? AND type = ?", user.Id, "password_reset")
 
-	code := generateCode()
+	// В ссылке передается длинный случайный токен, в базе хранится только его хеш
+	token, err := generateRefreshSecret()
+	if err != nil {
+		return err
+	}
 	expiresAt := time.Now().Add(10 * time.Minute)
 
 	verification := models.VerificationCode{
 		UserId:    user.Id,
-		Code:      code,
+		Code:      hashRefreshSecret(token),
 		Type:      "password_reset",
 		ExpiresAt: expiresAt,
 	}
 	repository.DB.Create(&verification)
 
 	// Формируем ссылку
-	resetLink := fmt.Sprintf("https://blogpoint.com/reset-password?token=%s", code)
+	resetLink := fmt.Sprintf("%s/reset-password?token=%s", mail.SiteURL(), token)
 
 	// Отправляем email
-	subject := "Blog point password recovery"
-	content := fmt.Sprintf(`
-		<h1>Password recovery</h1>
-		<p>Click the link below to reset your password:</p>
-		<a href="%s">%s</a>
-		<p>This link is valid for 10 minutes.</p>
-		<p>Best regards, <br>Blog Point Team</p>`, resetLink, resetLink)
-
-	to := []string{email}
-
-	err := emailSender.SendEmail(subject, content, to, nil, nil, nil)
-
+	message, err := mail.Render(mail.TemplatePasswordReset, user.Language, mail.LinkData{
+		Link:         resetLink,
+		ValidMinutes: 10,
+	})
 	if err != nil {
 		return err
 	}
 
+	if err = emailSender.SendMessage(message, []string{email}); err != nil {
+		return err
+	}
+
 	return c.JSON(MessageResponse{
 		Message: "Password recovery link sent"})
 
@@ -831,13 +830,14 @@ func RequestPasswordReset(c *fiber.Ctx, emailSender mail.EmailSender) error {
 
 // ResetPassword сбрасывает пароль
 // @Summary      Сброс пароля
-// @Description  Сброс пароля по коду, отправленному на почту
+// @Description  Сброс пароля по токену из ссылки, отправленной на почту
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
-// @Param        data  body      ResetPasswordRequest true "Код и новый пароль"
+// @Param        data  body      ResetPasswordRequest true "Токен и новый пароль"
 // @Success      200   {object}  MessageResponse
 // @Failure      400   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
 // @Router       /api/resetPassword [patch]
 func ResetPassword(c *fiber.Ctx) error {
 	var data ResetPasswordRequest
@@ -858,7 +858,7 @@ func ResetPassword(c *fiber.Ctx) error {
 
 	var verification models.VerificationCode
 	err := repository.DB.Where("code = ? AND type = ? AND expires_at > ?",
-		data.Code, "password_reset", time.Now()).First(&verification).Error
+		hashRefreshSecret(data.Code), "password_reset", time.Now()).First(&verification).Error
 
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid or expired code"})
@@ -870,8 +870,22 @@ func ResetPassword(c *fiber.Ctx) error {
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(newPassword), 14)
 	user.Password = hashedPassword
+	// Новый пароль снимает блокировку входа
+	user.FailedLogins = 0
+	user.LockedUntil = nil
 	repository.DB.Save(&user)
 
+	// Сбрасываем все сессии пользователя; текущую оставляем, только если запрос пришел из нее
+	var keepSessionId uint
+	if session := CurrentSession(c); session != nil && session.UserId == user.Id {
+		keepSessionId = session.Id
+	}
+	if _, err = revokeUserSessions(user.Id, keepSessionId); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to revoke sessions",
+		})
+	}
+
 	// Удаляем использованный токен
 	repository.DB.Delete(&verification)
 
@@ -881,50 +895,33 @@ func ResetPassword(c *fiber.Ctx) error {
 
 // RequestDeletionVerification отправляет код для удаления аккаунта на почту
 // @Summary      Отправить код подтверждения удаления аккаунта
-// @Description  Отправляет код подтверждения на email для удаления аккаунта
+// @Description  Отправляет код подтверждения на email для удаления аккаунта. Число писем на аккаунт ограничено
 // @Tags         User
 // @Security     ApiKeyAuth
 // @Produce      json
 // @Success      200  {object}  MessageResponse
 // @Failure      401  {object}  ErrorResponse
+// @Failure      429  {object}  ErrorResponse
 // @Router       /api/requestDeletionVerification [post]
-func RequestDeletionVerification(c *fiber.Ctx, emailSender mail.EmailSender) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
+func RequestDeletionVerification(c *fiber.Ctx, emailSender mail.EmailSender, limiter *ratelimit.Limiter) error {
+	user := CurrentUser(c)
 
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	uintId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
+	if err := checkRateLimit(c, limiter, "account_deletion:"+strconv.FormatUint(uint64(user.Id), 10)); err != nil {
+		return c.Status(err.Code).JSON(ErrorResponse{Message: err.Message})
 	}
 
 	// Удаляем старый код
-	repository.DB.Delete(&models.VerificationCode{}, "user_id = ? AND type = ?", uintId, "account_deletion")
+	repository.DB.Delete(&models.VerificationCode{}, "user_id = ? AND type = ?", user.Id, "account_deletion")
 
 	// Генерируем код
-	code := generateCode()
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
 	expiresAt := time.Now().Add(10 * time.Minute)
 
 	verification := models.VerificationCode{
-		UserId:    uint(uintId),
+		UserId:    user.Id,
 		Code:      code,
 		Type:      "account_deletion",
 		ExpiresAt: expiresAt,
@@ -932,32 +929,25 @@ func RequestDeletionVerification(c *fiber.Ctx, emailSender mail.EmailSender) err
 	repository.DB.Create(&verification)
 
 	// Отправляем email
-	var user models.User
-	repository.DB.First(&user, uintId)
-
-	subject := "Blog point verification code"
-	content := fmt.Sprintf(`
-	<h1>Deletion confirmation</h1>
-	<p>Your verification code is: <strong>%s</strong></p>
-	<p>Please enter this code on the website to delete your account.</p>
-	<p>This code will expire in 10 minutes.</p>
-	<p>Best regards, <br>Blog Point Team</p>`, code)
-
-	to := []string{user.Email}
-
-	err = emailSender.SendEmail(subject, content, to, nil, nil, nil)
-
+	message, err := mail.Render(mail.TemplateAccountDeletion, user.Language, mail.CodeData{
+		Code:         code,
+		ValidMinutes: 10,
+	})
 	if err != nil {
 		return err
 	}
 
+	if err = emailSender.SendMessage(message, []string{user.Email}); err != nil {
+		return err
+	}
+
 	return c.JSON(MessageResponse{
 		Message: "Deletion confirmation code sent"})
 }
 
 // DeleteUser удаляет аккаунт
 // @Summary      Удаление аккаунта
-// @Description  Удаляет аккаунт пользователя по коду подтверждения
+// @Description  Удаляет аккаунт пользователя по коду подтверждения. После 5 неверных вводов код аннулируется
 // @Tags         User
 // @Security     ApiKeyAuth
 // @Accept       json
@@ -966,6 +956,7 @@ func RequestDeletionVerification(c *fiber.Ctx, emailSender mail.EmailSender) err
 // @Success      200   {object}  MessageResponse
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
 // @Router       /api/deleteUser [delete]
 func DeleteUser(c *fiber.Ctx) error {
 	var data CodeRequest
@@ -974,31 +965,15 @@ func DeleteUser(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
+	user := CurrentUser(c)
 
 	// Проверяем код
-	var verification models.VerificationCode
-	err = repository.DB.Where(
-		"user_id = ? AND code = ? AND type = ? AND expires_at > ?",
-		claims["iss"], data.Code, "account_deletion", time.Now(),
-	).First(&verification).Error
-
+	verification, err := checkVerificationCode(user.Id, "account_deletion", data.Code)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid or expired code"})
+		return verificationCodeError(c, err)
 	}
 
-	repository.DB.Delete(&models.User{}, "id = ?", claims["iss"])
+	repository.DB.Delete(&models.User{}, "id = ?", user.Id)
 
 	repository.DB.Delete(&verification)
 
@@ -1010,10 +985,13 @@ func DeleteUser(c *fiber.Ctx) error {
 func ConvertUserToResponse(user models.User, file *models.File) UserResponse {
 	var logo *FileResponse
 	if file != nil {
-		logo = &FileResponse{
-			Id:  file.Id,
-			Url: storage.GetUrl(file.Filename)}
+		response := newFileResponse(*file)
+		logo = &response
+	}
 
+	role := models.RoleUser
+	if user.Role != nil {
+		role = user.Role.Name
 	}
 
 	return UserResponse{
@@ -1022,6 +1000,8 @@ func ConvertUserToResponse(user models.User, file *models.File) UserResponse {
 		Email:      user.Email,
 		Language:   user.Language,
 		IsVerified: user.IsVerified,
+		Role:       role,
+		TwoFactor:  user.TotpEnabled,
 		Logo:       logo,
 	}
 }
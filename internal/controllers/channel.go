@@ -1,13 +1,15 @@
 package controllers
 
 import (
+	"blogpoint-backend/internal/media"
 	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/notify"
+	"blogpoint-backend/internal/pagination"
 	"blogpoint-backend/internal/repository"
 	"blogpoint-backend/internal/storage"
 	"encoding/json"
 	"errors"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 	"log"
 	"strconv"
@@ -35,32 +37,7 @@ func CreateChannel(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
+	user := CurrentUser(c)
 
 	var existingChannel models.Channel
 	if result := repository.DB.Where("name = ?", data.Name).First(&existingChannel); result.Error == nil {
@@ -87,13 +64,27 @@ func CreateChannel(c *fiber.Ctx) error {
 		}
 	}
 
+	channelSlug, err := repository.UniqueChannelSlug(repository.DB, data.Name, 0)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to generate channel slug",
+		})
+	}
+
 	channel := models.Channel{
 		Name:        data.Name,
+		Slug:        channelSlug,
 		Description: data.Description,
 		CategoryId:  data.CategoryId,
-		OwnerId:     uint(userId),
+		OwnerId:     user.Id,
+	}
+	if err = repository.DB.Create(&channel).Error; err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to create channel",
+		})
 	}
-	repository.DB.Create(&channel)
 	repository.DB.Preload("Category").First(&channel, channel.Id)
 
 	var logo *models.File
@@ -141,32 +132,7 @@ func EditChannel(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	Id, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	if data.ChannelId == 0 {
 		c.Status(fiber.StatusBadRequest)
@@ -183,13 +149,14 @@ func EditChannel(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(Id) {
+	if !canManageChannel(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this channel",
 		})
 	}
 
+	nameChanged := data.Name != "" && data.Name != channel.Name
 	if data.Name != "" {
 		channel.Name = data.Name
 	}
@@ -212,7 +179,15 @@ func EditChannel(c *fiber.Ctx) error {
 		}
 	}
 
-	if err := repository.DB.Save(&channel).Error; err != nil {
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if nameChanged {
+			if err := updateChannelSlug(tx, &channel); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&channel).Error
+	})
+	if err != nil {
 		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
 			Message: "Failed to update channel",
@@ -245,7 +220,7 @@ func EditChannel(c *fiber.Ctx) error {
 
 // UploadChannelLogo загружает логотип канала
 // @Summary      Загрузка логотипа канала
-// @Description  Загружает изображение и устанавливает его как логотип канала
+// @Description  Загружает изображение и устанавливает его как логотип канала. Создаются квадратные варианты avatar_64, avatar_128 и avatar_256
 // @Tags         Channel
 // @Security     ApiKeyAuth
 // @Accept       multipart/form-data
@@ -255,40 +230,11 @@ func EditChannel(c *fiber.Ctx) error {
 // @Success      200   {object}  DataResponse[FileResponse]
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
+// @Failure      413   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /api/uploadChannelLogo/{id} [post]
 func UploadChannelLogo(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
-
-	var user models.User
-	if err = repository.DB.First(&user, userId).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Message: "User not found"})
-	}
+	user := CurrentUser(c)
 
 	// Получаем id канала из path
 	channelId, err := strconv.ParseUint(c.Params("id"), 10, 32)
@@ -304,7 +250,7 @@ func UploadChannelLogo(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(userId) {
+	if !canManageChannel(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this channel",
@@ -316,18 +262,14 @@ func UploadChannelLogo(c *fiber.Ctx) error {
 		oldLogoId = *channel.LogoId
 	}
 
-	filename, mimeType, err := ProcessUpload(c, "image")
+	file, err := ProcessUpload(c, "image", media.PresetAvatar, models.FileVisibilityPublic)
 	if err != nil {
 		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
 			Message: err.(*fiber.Error).Error(),
 		})
 	}
 
-	file := models.File{
-		OwnerId:  uint(userId),
-		Filename: filename,
-		MimeType: mimeType,
-	}
+	file.OwnerId = user.Id
 
 	if err = repository.DB.Create(&file).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving file to DB"})
@@ -345,7 +287,7 @@ func UploadChannelLogo(c *fiber.Ctx) error {
 			})
 		}
 
-		if err = storage.DeleteFromMinIO(c.Context(), oldFile.Filename); err != nil {
+		if err = storage.DeleteFromMinIO(c.Context(), oldFile.Filename, oldFile.VariantObjects()...); err != nil {
 			return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
 				Message: err.(*fiber.Error).Error(),
 			})
@@ -358,15 +300,8 @@ func UploadChannelLogo(c *fiber.Ctx) error {
 		}
 	}
 
-	url := storage.GetUrl(filename)
-
-	fileResponse := FileResponse{
-		Id:  file.Id,
-		Url: url,
-	}
-
 	return c.JSON(DataResponse[FileResponse]{
-		Data:    fileResponse,
+		Data:    newFileResponse(file),
 		Message: "Channel logo uploaded successfully",
 	})
 }
@@ -384,39 +319,7 @@ func UploadChannelLogo(c *fiber.Ctx) error {
 // @Failure      500  {object}  ErrorResponse
 // @Router       /api/deleteChannelLogo/{id} [delete]
 func DeleteChannelLogo(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
-
-	var user models.User
-	if err = repository.DB.First(&user, "id = ?", userId).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
-			Message: "User not found",
-		})
-	}
+	user := CurrentUser(c)
 
 	channelId, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
@@ -431,7 +334,7 @@ func DeleteChannelLogo(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(userId) {
+	if !canManageChannel(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this channel",
@@ -451,7 +354,7 @@ func DeleteChannelLogo(c *fiber.Ctx) error {
 		})
 	}
 
-	if err = storage.DeleteFromMinIO(c.Context(), file.Filename); err != nil {
+	if err = storage.DeleteFromMinIO(c.Context(), file.Filename, file.VariantObjects()...); err != nil {
 		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
 			Message: err.(*fiber.Error).Error(),
 		})
@@ -476,7 +379,7 @@ func DeleteChannelLogo(c *fiber.Ctx) error {
 
 // DeleteChannel удаляет канал
 // @Summary      Удаление канала
-// @Description  Удаляет канал, если пользователь является его владельцем
+// @Description  Удаляет канал, если пользователь является его владельцем, модератором или администратором
 // @Tags         Channel
 // @Security     ApiKeyAuth
 // @Accept       json
@@ -490,32 +393,7 @@ func DeleteChannelLogo(c *fiber.Ctx) error {
 // @Failure      500   {object}  ErrorResponse
 // @Router       /api/deleteChannel/{id} [delete]
 func DeleteChannel(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	channelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || channelId == 0 {
@@ -533,7 +411,7 @@ func DeleteChannel(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(userId) {
+	if !canManageChannel(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this channel",
@@ -554,46 +432,71 @@ func DeleteChannel(c *fiber.Ctx) error {
 
 // GetUserSubscriptions возвращает список каналов, на которые подписан пользователь
 // @Summary      Получение подписок пользователя
-// @Description  Возвращает список каналов, на которые подписан текущий пользователь
+// @Description  Возвращает список каналов, на которые подписан текущий пользователь, последние подписки сначала
 // @Tags         Channel
 // @Security     ApiKeyAuth
 // @Produce      json
-// @Success      200  {array}   DataResponse[[]ChannelResponse]
-// @Failure      401  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]ChannelResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
 // @Router       /api/getUserSubscriptions [get]
 func GetUserSubscriptions(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
+	user := CurrentUser(c)
 
+	params, err := parsePageParams(c, pagination.ByTime)
 	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
+		return invalidPageParams(c, err)
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
+	type subscribedChannel struct {
+		ChannelId uint
+		SignedAt  time.Time
+	}
 
-	var channels []models.Channel
-	if err := repository.DB.Joins("JOIN subscriptions ON subscriptions.channel_id = channels.id").
-		Where("subscriptions.user_id = ?", claims["iss"]).
-		Find(&channels).Error; err != nil {
+	query := repository.DB.Table("subscriptions").
+		Select("channel_id, signed_at").
+		Where("user_id = ?", user.Id)
+	if params.Cursor != nil {
+		query = query.Where("(signed_at, channel_id) < (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
+
+	var subscriptions []subscribedChannel
+	if err = query.Order("signed_at DESC, channel_id DESC").Limit(params.Limit + 1).Scan(&subscriptions).Error; err != nil {
 		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
 			Message: "Failed to retrieve subscriptions",
 		})
 	}
 
-	if len(channels) == 0 {
+	subscriptions, hasMore := pagination.Trim(subscriptions, params.Limit)
+
+	ids := make([]uint, len(subscriptions))
+	for i, subscription := range subscriptions {
+		ids[i] = subscription.ChannelId
+	}
+
+	var found []models.Channel
+	if err = repository.DB.Preload("Category").Where("id IN ?", ids).Find(&found).Error; err != nil {
+		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
-			Message: "No subscriptions found",
+			Message: "Failed to retrieve subscriptions",
 		})
 	}
 
-	var channelsResponse []ChannelResponse
-	for _, channel := range channels {
+	channelMap := make(map[uint]models.Channel, len(found))
+	for _, channel := range found {
+		channelMap[channel.Id] = channel
+	}
+
+	channelsResponse := make([]ChannelResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		channel, ok := channelMap[subscription.ChannelId]
+		if !ok {
+			continue
+		}
 
 		var logo *models.File
 		if channel.LogoId != nil {
@@ -612,14 +515,16 @@ func GetUserSubscriptions(c *fiber.Ctx) error {
 			}
 		}
 
-		repository.DB.Preload("Category").First(&channel, channel.Id)
-
 		channelsResponse = append(channelsResponse, ConvertChannelToResponse(channel, logo))
 	}
 
-	return c.JSON(DataResponse[[]ChannelResponse]{
-		Data: channelsResponse,
-	})
+	var last pagination.Cursor
+	if len(subscriptions) > 0 {
+		lastSubscription := subscriptions[len(subscriptions)-1]
+		last = pagination.TimeCursor(lastSubscription.SignedAt, lastSubscription.ChannelId)
+	}
+
+	return c.JSON(newPageResponse(channelsResponse, hasMore, last))
 }
 
 // GetUserChannels возвращает каналы, созданные пользователем
@@ -633,21 +538,10 @@ func GetUserSubscriptions(c *fiber.Ctx) error {
 // @Failure      500  {object}  ErrorResponse
 // @Router       /api/getUserChannels [get]
 func GetUserChannels(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	claims := token.Claims.(jwt.MapClaims)
+	user := CurrentUser(c)
 
 	var channels []models.Channel
-	if err := repository.DB.Where("owner_id = ?", claims["iss"]).Find(&channels).Error; err != nil {
+	if err := repository.DB.Where("owner_id = ?", user.Id).Find(&channels).Error; err != nil {
 		c.Status(fiber.StatusInternalServerError)
 		return c.JSON(ErrorResponse{
 			Message: "Failed to retrieve channels",
@@ -725,6 +619,85 @@ func GetChannel(c *fiber.Ctx) error {
 		})
 	}
 
+	return channelDetailsResponse(c, channel)
+}
+
+// GetChannelBySlug возвращает информацию о канале по слагу
+// @Summary      Получение канала по слагу
+// @Description  Возвращает информацию о канале по слагу. Если канал переименован, со старого слага отвечает 301 с адресом по текущему слагу.
+// @Tags         Channel
+// @Produce      json
+// @Param        slug  path      string true "Слаг канала"
+// @Success      200   {object}  DataResponse[ChannelResponse]
+// @Success      301   {string}  string "Перенаправление на текущий адрес канала"
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/getChannelBySlug/{slug} [get]
+func GetChannelBySlug(c *fiber.Ctx) error {
+	channel, redirected, err := findChannelBySlug(c.Params("slug"))
+	if err != nil {
+		return slugLookupError(c, err, "Channel")
+	}
+
+	if redirected {
+		return c.Redirect("/api/getChannelBySlug/"+channel.Slug, fiber.StatusMovedPermanently)
+	}
+
+	return channelDetailsResponse(c, channel)
+}
+
+// findChannelBySlug ищет канал по текущему слагу, а затем среди старых.
+// redirected сообщает, что канал найден по старому слагу.
+func findChannelBySlug(channelSlug string) (channel models.Channel, redirected bool, err error) {
+	err = repository.DB.Where("slug = ?", channelSlug).First(&channel).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return channel, false, err
+	}
+
+	var redirect models.ChannelSlugRedirect
+	if err = repository.DB.Where("slug = ?", channelSlug).First(&redirect).Error; err != nil {
+		return channel, false, err
+	}
+
+	err = repository.DB.First(&channel, redirect.ChannelId).Error
+	return channel, true, err
+}
+
+// slugLookupError отвечает 404 на ненайденную запись и 500 на прочие ошибки. entity — "Channel" или "Post".
+func slugLookupError(c *fiber.Ctx, err error, entity string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: entity + " not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+		Message: "Failed to retrieve " + strings.ToLower(entity),
+	})
+}
+
+// updateChannelSlug подбирает слаг под новое название канала и сохраняет перенаправление со старого слага
+func updateChannelSlug(tx *gorm.DB, channel *models.Channel) error {
+	newSlug, err := repository.UniqueChannelSlug(tx, channel.Name, channel.Id)
+	if err != nil || newSlug == channel.Slug {
+		return err
+	}
+
+	if err = repository.SaveChannelSlugRedirect(tx, channel.Slug, channel.Id); err != nil {
+		return err
+	}
+
+	// Канал мог вернуться к одному из своих прежних слагов
+	if err = tx.Where("slug = ? AND channel_id = ?", newSlug, channel.Id).
+		Delete(&models.ChannelSlugRedirect{}).Error; err != nil {
+		return err
+	}
+
+	channel.Slug = newSlug
+	return nil
+}
+
+// channelDetailsResponse отдает канал с логотипом и категорией
+func channelDetailsResponse(c *fiber.Ctx, channel models.Channel) error {
 	var logo *models.File
 	if channel.LogoId != nil {
 		if err := repository.DB.First(&logo, *channel.LogoId).Error; err != nil {
@@ -748,27 +721,35 @@ func GetChannel(c *fiber.Ctx) error {
 
 // GetPopularChannels возвращает популярные каналы
 // @Summary      Получение популярных каналов
-// @Description  Возвращает список каналов, отсортированных по количеству подписчиков по убыванию
+// @Description  Возвращает список каналов, отсортированных по количеству подписчиков по убыванию, с курсорной пагинацией
 // @Tags         Channel
 // @Produce      json
-// @Success      200  {array}   DataResponse[[]ChannelResponse]
-// @Failure      500  {object}  ErrorResponse
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]ChannelResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
 // @Router       /api/getPopularChannels [get]
 func GetPopularChannels(c *fiber.Ctx) error {
-	var channels []models.Channel
+	params, err := parsePageParams(c, pagination.ByScore)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
 
-	if err := repository.DB.Order("subs_count DESC").Find(&channels).Error; err != nil {
+	query := repository.DB.Preload("Category")
+	if params.Cursor != nil {
+		query = query.Where("(subs_count, id) < (?, ?)", int64(*params.Cursor.Score), params.Cursor.Id)
+	}
+
+	var channels []models.Channel
+	if err = query.Order("subs_count DESC, id DESC").Limit(params.Limit + 1).Find(&channels).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Message: "Failed to retrieve popular channels"})
 	}
 
-	if len(channels) == 0 {
-		return c.JSON(ErrorResponse{
-			Message: "No channels found",
-		})
-	}
+	channels, hasMore := pagination.Trim(channels, params.Limit)
 
-	var channelsResponse []ChannelResponse
+	channelsResponse := make([]ChannelResponse, 0, len(channels))
 	for _, channel := range channels {
 
 		var logo *models.File
@@ -788,14 +769,16 @@ func GetPopularChannels(c *fiber.Ctx) error {
 			}
 		}
 
-		repository.DB.Preload("Category").First(&channel, channel.Id)
-
 		channelsResponse = append(channelsResponse, ConvertChannelToResponse(channel, logo))
 	}
 
-	return c.JSON(DataResponse[[]ChannelResponse]{
-		Data: channelsResponse,
-	})
+	var last pagination.Cursor
+	if len(channels) > 0 {
+		lastChannel := channels[len(channels)-1]
+		last = pagination.ScoreCursor(float64(lastChannel.SubsCount), lastChannel.Id)
+	}
+
+	return c.JSON(newPageResponse(channelsResponse, hasMore, last))
 }
 
 // SubscribeChannel подписывает пользователя на канал
@@ -812,33 +795,8 @@ func GetPopularChannels(c *fiber.Ctx) error {
 // @Failure      404   {object}  ErrorResponse
 // @Failure      409   {object}  ErrorResponse
 // @Router       /api/subscribeChannel/{id} [post]
-func SubscribeChannel(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
+func SubscribeChannel(c *fiber.Ctx, notifier *notify.Dispatcher) error {
+	user := CurrentUser(c)
 
 	channelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || channelId == 0 {
@@ -857,7 +815,7 @@ func SubscribeChannel(c *fiber.Ctx) error {
 	}
 
 	var subscription models.Subscription
-	if result := repository.DB.Where("user_id = ? AND channel_id = ?", userId, channelId).First(&subscription); result.Error == nil {
+	if result := repository.DB.Where("user_id = ? AND channel_id = ?", user.Id, channelId).First(&subscription); result.Error == nil {
 		c.Status(fiber.StatusConflict)
 		return c.JSON(ErrorResponse{
 			Message: "Already subscribed",
@@ -865,14 +823,30 @@ func SubscribeChannel(c *fiber.Ctx) error {
 	}
 
 	subscription = models.Subscription{
-		UserId:    uint(userId),
+		UserId:    user.Id,
 		ChannelId: uint(channelId),
 	}
 
-	repository.DB.Create(&subscription)
+	if err = repository.DB.Create(&subscription).Error; err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Failed to subscribe",
+		})
+	}
 
 	repository.DB.Model(&channel).Update("subs_count", channel.SubsCount+1)
 
+	notifier.Notify(notify.Event{
+		Type:      notify.TypeNewSubscriber,
+		UserIds:   []uint{channel.OwnerId},
+		ActorId:   &user.Id,
+		ChannelId: &channel.Id,
+		Data: models.NotificationData{
+			ActorLogin:  user.Login,
+			ChannelName: channel.Name,
+		},
+	})
+
 	return c.JSON(MessageResponse{
 		Message: "Subscription successful",
 	})
@@ -893,32 +867,7 @@ func SubscribeChannel(c *fiber.Ctx) error {
 // @Failure      404   {object}  ErrorResponse
 // @Router       /api/unsubscribeChannel/{id} [delete]
 func UnsubscribeChannel(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
+	user := CurrentUser(c)
 
 	channelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || channelId == 0 {
@@ -936,7 +885,7 @@ func UnsubscribeChannel(c *fiber.Ctx) error {
 		})
 	}
 
-	result := repository.DB.Where("user_id = ? AND channel_id = ?", userId, channelId).Delete(&models.Subscription{})
+	result := repository.DB.Where("user_id = ? AND channel_id = ?", user.Id, channelId).Delete(&models.Subscription{})
 	if result.RowsAffected == 0 {
 		c.Status(fiber.StatusNotFound)
 		return c.JSON(ErrorResponse{
@@ -968,32 +917,7 @@ func UnsubscribeChannel(c *fiber.Ctx) error {
 // @Failure      404      {object}  ErrorResponse
 // @Router       /api/getChannelStatistics/{id} [Get]
 func GetChannelStatistics(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
+	user := CurrentUser(c)
 
 	channelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || channelId == 0 {
@@ -1011,7 +935,7 @@ func GetChannelStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(userId) {
+	if !canManageChannel(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this channel",
@@ -1151,15 +1075,14 @@ func GetAllTags(c *fiber.Ctx) error {
 func ConvertChannelToResponse(channel models.Channel, file *models.File) ChannelResponse {
 	var logo *FileResponse
 	if file != nil {
-		logo = &FileResponse{
-			Id:  file.Id,
-			Url: storage.GetUrl(file.Filename)}
-
+		response := newFileResponse(*file)
+		logo = &response
 	}
 
 	return ChannelResponse{
 		Id:          channel.Id,
 		Name:        channel.Name,
+		Slug:        channel.Slug,
 		Description: channel.Description,
 		Category:    channel.Category,
 		OwnerId:     channel.OwnerId,
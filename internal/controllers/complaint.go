@@ -0,0 +1,462 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/pagination"
+	"blogpoint-backend/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Действия, которые можно применить к объекту жалобы при ее закрытии
+const (
+	complaintActionNone = "none"
+	complaintActionHide = "hide"
+)
+
+var complaintTypes = map[string]bool{
+	"spam":           true,
+	"abuse":          true,
+	"illegal":        true,
+	"misinformation": true,
+	"other":          true,
+}
+
+var complaintStatuses = map[string]bool{
+	models.ComplaintStatusOpen:       true,
+	models.ComplaintStatusInProgress: true,
+	models.ComplaintStatusClosed:     true,
+}
+
+var complaintTargetTypes = map[string]bool{
+	models.ComplaintTargetChannel: true,
+	models.ComplaintTargetPost:    true,
+	models.ComplaintTargetComment: true,
+}
+
+var errComplaintTargetNotFound = errors.New("complaint target not found")
+
+// CreateComplaint создает жалобу на канал, пост или комментарий
+// @Summary      Отправка жалобы
+// @Description  Создает жалобу на канал, пост или комментарий. Типы жалоб: spam, abuse, illegal, misinformation, other. Повторная открытая жалоба на тот же объект не принимается.
+// @Tags         Complaint
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      CreateComplaintRequest true "Объект и описание жалобы"
+// @Success      200   {object}  DataResponse[ComplaintResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/createComplaint [post]
+func CreateComplaint(c *fiber.Ctx) error {
+	var data CreateComplaintRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	if !complaintTypes[data.ComplaintType] {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid complaint type",
+		})
+	}
+
+	data.Description = strings.TrimSpace(data.Description)
+	if data.Description == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Description is required",
+		})
+	}
+
+	if _, err := complaintTargetChannel(data.TargetType, data.TargetId); err != nil {
+		if errors.Is(err, errComplaintTargetNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Message: "Complaint target not found",
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid target type",
+		})
+	}
+
+	var existing int64
+	repository.DB.Model(&models.Complaint{}).
+		Where("user_id = ? AND target_type = ? AND target_id = ? AND status <> ?",
+			user.Id, data.TargetType, data.TargetId, models.ComplaintStatusClosed).
+		Count(&existing)
+	if existing > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "You have already reported this",
+		})
+	}
+
+	complaint := models.Complaint{
+		UserId:        user.Id,
+		TargetType:    data.TargetType,
+		TargetId:      data.TargetId,
+		ComplaintType: data.ComplaintType,
+		Description:   data.Description,
+		Status:        models.ComplaintStatusOpen,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := repository.DB.Create(&complaint).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to create complaint",
+		})
+	}
+
+	return c.JSON(DataResponse[ComplaintResponse]{
+		Data:    ConvertComplaintToResponse(complaint),
+		Message: "Complaint submitted successfully",
+	})
+}
+
+// GetComplaints возвращает жалобы для модерации
+// @Summary      Список жалоб
+// @Description  Возвращает жалобы с фильтрами по статусу, типу объекта, типу жалобы и исполнителю, старые сначала, с курсорной пагинацией. Доступно модераторам и администраторам.
+// @Tags         Complaint
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        status         query     string false "Статус: open, in progress, close"
+// @Param        targetType     query     string false "Тип объекта: channel, post, comment"
+// @Param        complaintType  query     string false "Тип жалобы"
+// @Param        assignee       query     string false "me — только взятые текущим модератором, none — невзятые"
+// @Param        cursor         query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit          query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200            {object}  PageResponse[[]ComplaintResponse]
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/getComplaints [get]
+func GetComplaints(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	params, err := parsePageParams(c, pagination.ByTime)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	query := repository.DB.Model(&models.Complaint{})
+	if params.Cursor != nil {
+		query = query.Where("(created_at, id) > (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
+
+	if status := c.Query("status"); status != "" {
+		if !complaintStatuses[status] {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Invalid status",
+			})
+		}
+		query = query.Where("status = ?", status)
+	}
+	if targetType := c.Query("targetType"); targetType != "" {
+		if !complaintTargetTypes[targetType] {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Invalid target type",
+			})
+		}
+		query = query.Where("target_type = ?", targetType)
+	}
+	if complaintType := c.Query("complaintType"); complaintType != "" {
+		if !complaintTypes[complaintType] {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Invalid complaint type",
+			})
+		}
+		query = query.Where("complaint_type = ?", complaintType)
+	}
+	switch c.Query("assignee") {
+	case "":
+	case "me":
+		query = query.Where("assignee_id = ?", user.Id)
+	case "none":
+		query = query.Where("assignee_id IS NULL")
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid assignee filter",
+		})
+	}
+
+	var complaints []models.Complaint
+	if err = query.Order("created_at ASC, id ASC").Limit(params.Limit + 1).Find(&complaints).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch complaints",
+		})
+	}
+
+	complaints, hasMore := pagination.Trim(complaints, params.Limit)
+
+	response := make([]ComplaintResponse, 0, len(complaints))
+	for _, complaint := range complaints {
+		response = append(response, ConvertComplaintToResponse(complaint))
+	}
+
+	var last pagination.Cursor
+	if len(complaints) > 0 {
+		lastComplaint := complaints[len(complaints)-1]
+		last = pagination.TimeCursor(lastComplaint.CreatedAt, lastComplaint.Id)
+	}
+
+	return c.JSON(newPageResponse(response, hasMore, last))
+}
+
+// ClaimComplaint берет открытую жалобу в работу
+// @Summary      Взять жалобу в работу
+// @Description  Назначает текущего модератора исполнителем открытой жалобы и переводит ее в статус in progress
+// @Tags         Complaint
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int true "Id жалобы"
+// @Success      200  {object}  DataResponse[ComplaintResponse]
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/claimComplaint/{id} [patch]
+func ClaimComplaint(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	complaintId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || complaintId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid complaint Id"})
+	}
+
+	var complaint models.Complaint
+	if err = repository.DB.First(&complaint, complaintId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Complaint not found",
+		})
+	}
+
+	// Условие на статус не дает двум модераторам одновременно взять одну жалобу
+	result := repository.DB.Model(&complaint).
+		Where("status = ?", models.ComplaintStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      models.ComplaintStatusInProgress,
+			"assignee_id": user.Id,
+		})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to claim complaint",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Message: "Complaint is already claimed or closed",
+		})
+	}
+
+	complaint.Status = models.ComplaintStatusInProgress
+	complaint.AssigneeId = &user.Id
+
+	return c.JSON(DataResponse[ComplaintResponse]{
+		Data:    ConvertComplaintToResponse(complaint),
+		Message: "Complaint claimed",
+	})
+}
+
+// ResolveComplaint закрывает жалобу с решением и, при необходимости, скрывает объект жалобы
+// @Summary      Закрытие жалобы
+// @Description  Закрывает жалобу с текстом решения. Действие hide скрывает пост или комментарий, none оставляет объект без изменений. Закрыть можно свою или невзятую жалобу, администратор может закрыть любую. Автор жалобы получает письмо с решением.
+// @Tags         Complaint
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      ResolveComplaintRequest true "Решение по жалобе"
+// @Success      200   {object}  DataResponse[ComplaintResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/resolveComplaint [patch]
+func ResolveComplaint(c *fiber.Ctx, emailSender mail.EmailSender) error {
+	var data ResolveComplaintRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	data.Resolution = strings.TrimSpace(data.Resolution)
+	if data.Resolution == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Resolution is required",
+		})
+	}
+
+	if data.Action == "" {
+		data.Action = complaintActionNone
+	}
+	if data.Action != complaintActionNone && data.Action != complaintActionHide {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid action",
+		})
+	}
+
+	var complaint models.Complaint
+	if err := repository.DB.First(&complaint, data.ComplaintId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Complaint not found",
+		})
+	}
+
+	if complaint.Status == models.ComplaintStatusClosed {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Complaint is already closed",
+		})
+	}
+
+	if complaint.AssigneeId != nil && *complaint.AssigneeId != user.Id && !user.HasRole(models.RoleAdmin) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "Complaint is assigned to another moderator",
+		})
+	}
+
+	if data.Action == complaintActionHide && complaint.TargetType == models.ComplaintTargetChannel {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Channels cannot be hidden",
+		})
+	}
+
+	channelId, err := complaintTargetChannel(complaint.TargetType, complaint.TargetId)
+	if err != nil && data.Action == complaintActionHide {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Complaint target not found",
+		})
+	}
+
+	now := time.Now()
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		if data.Action == complaintActionHide {
+			var table string
+			if complaint.TargetType == models.ComplaintTargetPost {
+				table = "posts"
+			} else {
+				table = "comments"
+			}
+			if err := tx.Table(table).Where("id = ?", complaint.TargetId).Update("is_hidden", true).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&complaint).Updates(map[string]interface{}{
+			"status":      models.ComplaintStatusClosed,
+			"assignee_id": user.Id,
+			"resolution":  data.Resolution,
+			"action":      data.Action,
+			"resolved_at": now,
+		}).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to resolve complaint",
+		})
+	}
+
+	complaint.Status = models.ComplaintStatusClosed
+	complaint.AssigneeId = &user.Id
+	complaint.Resolution = data.Resolution
+	complaint.Action = data.Action
+	complaint.ResolvedAt = &now
+
+	if data.Action == complaintActionHide {
+		action := actionHidePost
+		if complaint.TargetType == models.ComplaintTargetComment {
+			action = actionHideComment
+		}
+		logModeration(user, channelId, action, complaint.TargetType, complaint.TargetId,
+			fmt.Sprintf("Complaint %d", complaint.Id))
+	}
+
+	notifyComplaintResolved(emailSender, complaint)
+
+	return c.JSON(DataResponse[ComplaintResponse]{
+		Data:    ConvertComplaintToResponse(complaint),
+		Message: "Complaint resolved",
+	})
+}
+
+// complaintTargetChannel проверяет существование объекта жалобы и возвращает Id канала, к которому он относится
+func complaintTargetChannel(targetType string, targetId uint) (uint, error) {
+	var channelId uint
+	var query *gorm.DB
+
+	switch targetType {
+	case models.ComplaintTargetChannel:
+		query = repository.DB.Table("channels").Select("id").Where("id = ?", targetId)
+	case models.ComplaintTargetPost:
+		query = repository.DB.Table("posts").Select("channel_id").Where("id = ?", targetId)
+	case models.ComplaintTargetComment:
+		query = repository.DB.Table("comments").
+			Select("posts.channel_id").
+			Joins("JOIN posts ON posts.id = comments.post_id").
+			Where("comments.id = ?", targetId)
+	default:
+		return 0, fmt.Errorf("unknown complaint target type %q", targetType)
+	}
+
+	result := query.Limit(1).Scan(&channelId)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, errComplaintTargetNotFound
+	}
+
+	return channelId, nil
+}
+
+// notifyComplaintResolved сообщает автору жалобы о решении. Ошибка отправки не отменяет закрытие жалобы.
+func notifyComplaintResolved(emailSender mail.EmailSender, complaint models.Complaint) {
+	var reporter models.User
+	if err := repository.DB.First(&reporter, complaint.UserId).Error; err != nil {
+		return
+	}
+
+	message, err := mail.Render(mail.TemplateComplaintResolved, reporter.Language, mail.ComplaintResolvedData{
+		TargetType:    complaint.TargetType,
+		ComplaintType: complaint.ComplaintType,
+		Resolution:    complaint.Resolution,
+	})
+	if err == nil {
+		err = emailSender.SendMessage(message, []string{reporter.Email})
+	}
+	if err != nil {
+		log.Printf("Не удалось отправить уведомление по жалобе %d: %v", complaint.Id, err)
+	}
+}
+
+func ConvertComplaintToResponse(complaint models.Complaint) ComplaintResponse {
+	return ComplaintResponse{
+		Id:            complaint.Id,
+		UserId:        complaint.UserId,
+		TargetType:    complaint.TargetType,
+		TargetId:      complaint.TargetId,
+		ComplaintType: complaint.ComplaintType,
+		Description:   complaint.Description,
+		Status:        complaint.Status,
+		AssigneeId:    complaint.AssigneeId,
+		Resolution:    complaint.Resolution,
+		Action:        complaint.Action,
+		ResolvedAt:    complaint.ResolvedAt,
+		CreatedAt:     complaint.CreatedAt,
+	}
+}
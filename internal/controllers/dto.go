@@ -10,13 +10,48 @@ type DataResponse[T any] struct {
 	Message string `json:"message,omitempty"`
 }
 
+// PageResponse — DataResponse для списков с курсорной пагинацией.
+// NextCursor передается в параметре cursor следующего запроса.
+type PageResponse[T any] struct {
+	Data       T      `json:"data"`
+	Message    string `json:"message,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJ0IjoiMjAyNS0wNS0wMVQxMDowMDowMFoiLCJpZCI6NDJ9"`
+	HasMore    bool   `json:"hasMore" example:"true"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
 
+// FileResponse — файл и URL его вариантов (avatar_64/128/256 для лого, preview_640/1280 для изображений постов).
+// Для закрытого файла URL временные, ExpiresAt — когда они перестанут действовать.
 type FileResponse struct {
-	Id  uint   `json:"id"`
-	Url string `json:"url"`
+	Id         uint              `json:"id"`
+	Url        string            `json:"url"`
+	Visibility string            `json:"visibility" example:"public"`
+	Variants   map[string]string `json:"variants,omitempty"`
+	ExpiresAt  *time.Time        `json:"expiresAt,omitempty"`
+}
+
+// UploadUrlResponse — ссылка для загрузки файла напрямую в хранилище. Файл отправляется запросом Method на Url
+// с заголовками Headers, затем загрузка подтверждается через /api/completeUpload/{uploadId}.
+type UploadUrlResponse struct {
+	UploadId  uint              `json:"uploadId" example:"12"`
+	Url       string            `json:"url" example:"https://files.blogpoint.example.com/blogpoint-bucket/video/3f2b6c1e-0d1a-4c47-9a57-1f0e9b1d2c3a.mp4?X-Amz-Algorithm=AWS4-HMAC-SHA256&..."`
+	Method    string            `json:"method" example:"PUT"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// ChunkedUploadResponse — состояние загрузки частями. Offset — число байт, полученных подряд с начала файла.
+type ChunkedUploadResponse struct {
+	UploadId       uint      `json:"uploadId" example:"13"`
+	Size           int64     `json:"size" example:"314572800"`
+	ChunkSize      int64     `json:"chunkSize" example:"8388608"`
+	Chunks         int       `json:"chunks" example:"38"`
+	ReceivedChunks []int     `json:"receivedChunks" example:"1,2,3"`
+	Offset         int64     `json:"offset" example:"25165824"`
+	ExpiresAt      time.Time `json:"expiresAt"`
 }
 
 type ErrorResponse struct {
@@ -29,12 +64,56 @@ type UserResponse struct {
 	Email      string        `json:"email"`
 	Language   string        `json:"language"`
 	IsVerified bool          `json:"isVerified"`
+	Role       string        `json:"role" example:"user"`
 	Logo       *FileResponse `json:"logo"`
+	TwoFactor  bool          `json:"twoFactor" example:"false"`
+}
+
+// LoginResponse — ответ на вход по паролю. Если у пользователя включена двухфакторная аутентификация,
+// cookie не выставляются: MfaToken нужно обменять на сессию через /api/loginMfa.
+type LoginResponse struct {
+	Message     string `json:"message" example:"Successful authorization"`
+	MfaRequired bool   `json:"mfaRequired"`
+	MfaToken    string `json:"mfaToken,omitempty"`
+}
+
+type TotpSetupResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	Uri    string `json:"uri" example:"otpauth://totp/Blog%20Point:johndoe?algorithm=SHA1&digits=6&issuer=Blog%20Point&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	// QrCode — PNG с URI в виде data URL
+	QrCode string `json:"qrCode" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes" example:"7KQ2-MX9D,P4TR-8WZN"`
+}
+
+type OIDCProviderResponse struct {
+	Name        string `json:"name" example:"google"`
+	DisplayName string `json:"displayName" example:"Google"`
+	LoginUrl    string `json:"loginUrl" example:"/api/oidc/google/login"`
+}
+
+type IdentityResponse struct {
+	Id        uint      `json:"id" example:"1"`
+	Provider  string    `json:"provider" example:"google"`
+	Email     string    `json:"email" example:"johndoe@gmail.com"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SessionResponse struct {
+	Id         uint      `json:"id" example:"3"`
+	UserAgent  string    `json:"userAgent" example:"Mozilla/5.0 (X11; Linux x86_64)"`
+	Ip         string    `json:"ip" example:"192.168.0.10"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	IsCurrent  bool      `json:"isCurrent"`
 }
 
 type ChannelResponse struct {
 	Id          uint             `json:"id"`
 	Name        string           `json:"name"`
+	Slug        string           `json:"slug" example:"novosti-tekhnologiy"`
 	Description string           `json:"description"`
 	Category    *models.Category `json:"category"`
 	OwnerId     uint             `json:"ownerId"`
@@ -47,22 +126,123 @@ type PostResponse struct {
 	ChannelId     uint           `json:"channelId"`
 	PreviewImage  *FileResponse  `json:"previewImage"`
 	Title         string         `json:"title"`
+	Slug          string         `json:"slug" example:"obzor-novogo-smartfona"`
 	Content       string         `json:"content"`
+	ContentFormat string         `json:"contentFormat" example:"markdown"`
+	ContentHtml   string         `json:"contentHtml" example:"<p>Something <strong>here</strong></p>"`
 	LikesCount    uint           `json:"likesCount"`
 	DislikesCount uint           `json:"dislikesCount"`
 	ViewsCount    uint           `json:"viewsCount"`
+	IsHidden      bool           `json:"isHidden"`
+	Status        string         `json:"status" example:"published"`
+	PublishAt     *time.Time     `json:"publishAt"`
+	PublishedAt   *time.Time     `json:"publishedAt"`
 	PostImages    []FileResponse `json:"postImages"`
 	PostFiles     []FileResponse `json:"postFiles"`
 	Tags          []models.Tag   `json:"tags"`
 	CreatedAt     time.Time      `json:"createdAt"`
 }
 
+type FeedPostResponse struct {
+	PostResponse
+	IsSeen bool `json:"isSeen" example:"false"`
+}
+
+type PostRevisionResponse struct {
+	Id          uint                `json:"id" example:"3"`
+	PostId      uint                `json:"postId" example:"1"`
+	AuthorId    *uint               `json:"authorId" example:"5"`
+	AuthorLogin string              `json:"authorLogin" example:"johndoe"`
+	Snapshot    models.PostSnapshot `json:"snapshot"`
+	CreatedAt   time.Time           `json:"createdAt"`
+}
+
+type DiffChunk struct {
+	Op   string `json:"op" example:"insert"`
+	Text string `json:"text" example:"New paragraph"`
+}
+
+type IdsDiff struct {
+	Added   []uint `json:"added"`
+	Removed []uint `json:"removed"`
+}
+
+type PostRevisionDiffResponse struct {
+	FromId         uint        `json:"fromId" example:"2"`
+	ToId           uint        `json:"toId" example:"3"`
+	Title          []DiffChunk `json:"title"`
+	Content        []DiffChunk `json:"content"`
+	PreviewImageId IdsDiff     `json:"previewImageId"`
+	Tags           IdsDiff     `json:"tags"`
+	PostImages     IdsDiff     `json:"postImages"`
+	PostFiles      IdsDiff     `json:"postFiles"`
+}
+
+type PostSearchResult struct {
+	Post    PostResponse `json:"post"`
+	Snippet string       `json:"snippet" example:"… настройка <mark>сервера</mark> на Go …"`
+	Rank    float64      `json:"rank" example:"0.42"`
+}
+
+type ChannelSearchResult struct {
+	Channel ChannelResponse `json:"channel"`
+	Snippet string          `json:"snippet" example:"Новости о <mark>Go</mark> и бэкенде"`
+	Rank    float64         `json:"rank" example:"0.31"`
+}
+
+type SearchResponse struct {
+	Posts    []PostSearchResult    `json:"posts"`
+	Channels []ChannelSearchResult `json:"channels"`
+}
+
+type ChannelModeratorResponse struct {
+	Id         uint      `json:"id" example:"5"`
+	Login      string    `json:"login" example:"johndoe"`
+	AssignedAt time.Time `json:"assignedAt"`
+}
+
+type ModerationLogResponse struct {
+	Id         uint      `json:"id" example:"1"`
+	ActorId    *uint     `json:"actorId" example:"5"`
+	Action     string    `json:"action" example:"delete_comment"`
+	TargetType string    `json:"targetType" example:"comment"`
+	TargetId   uint      `json:"targetId" example:"42"`
+	Details    string    `json:"details" example:"Post 7"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ReactionCountsResponse struct {
+	PostId        uint `json:"postId" example:"7"`
+	LikesCount    uint `json:"likesCount" example:"12"`
+	DislikesCount uint `json:"dislikesCount" example:"1"`
+}
+
+type NotificationResponse struct {
+	Id        uint                    `json:"id" example:"1"`
+	Type      string                  `json:"type" example:"comment_reply"`
+	ActorId   *uint                   `json:"actorId" example:"5"`
+	ChannelId *uint                   `json:"channelId" example:"2"`
+	PostId    *uint                   `json:"postId" example:"7"`
+	CommentId *uint                   `json:"commentId" example:"42"`
+	Data      models.NotificationData `json:"data"`
+	IsRead    bool                    `json:"isRead" example:"false"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type  string `json:"type" example:"comment_reply"`
+	InApp bool   `json:"inApp" example:"true"`
+	Email bool   `json:"email" example:"true"`
+}
+
 type CommentResponse struct {
 	Id           uint   `json:"id"`
 	PostId       uint   `json:"postId"`
 	ParentId     *uint  `json:"parentId,omitempty"`
 	Content      string `json:"content"`
+	ContentHtml  string `json:"contentHtml"`
 	IsDeleted    bool   `json:"isDeleted"`
+	IsHidden     bool   `json:"isHidden"`
 	RepliesCount int    `json:"repliesCount"`
 	User         struct {
 		Id    uint          `json:"id"`
@@ -71,6 +251,21 @@ type CommentResponse struct {
 	} `json:"user"`
 }
 
+type ComplaintResponse struct {
+	Id            uint       `json:"id" example:"12"`
+	UserId        uint       `json:"userId" example:"5"`
+	TargetType    string     `json:"targetType" example:"comment"`
+	TargetId      uint       `json:"targetId" example:"42"`
+	ComplaintType string     `json:"complaintType" example:"spam"`
+	Description   string     `json:"description" example:"Реклама в комментариях"`
+	Status        string     `json:"status" example:"open"`
+	AssigneeId    *uint      `json:"assigneeId" example:"2"`
+	Resolution    string     `json:"resolution" example:"Комментарий скрыт"`
+	Action        string     `json:"action" example:"hide"`
+	ResolvedAt    *time.Time `json:"resolvedAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
 type StatisticsResponse struct {
 	Current ChannelStatistics `json:"current"`
 	Delta   ChannelStatistics `json:"delta"`
@@ -113,6 +308,12 @@ type CodeRequest struct {
 	Code string `json:"code" example:"H4RF1G"`
 }
 
+// LoginMfaRequest — второй шаг входа. Code — текущий код TOTP или неиспользованный код восстановления.
+type LoginMfaRequest struct {
+	MfaToken string `json:"mfaToken"`
+	Code     string `json:"code" example:"492039"`
+}
+
 type EditProfileRequest struct {
 	Login string `json:"login" example:"johndoe"`
 	Email string `json:"email" example:"user@example.com"`
@@ -128,10 +329,38 @@ type EmailRequest struct {
 }
 
 type ResetPasswordRequest struct {
-	Code     string `json:"code" example:"H4RF1G"`
+	Code     string `json:"code" example:"q8Zt0v3kR1x2cN5mW7yB9dE4fH6jL8pQ0sT2uV4wX6z"`
 	Password string `json:"password" example:"secret123"`
 }
 
+type SetUserRoleRequest struct {
+	UserId uint   `json:"userId" example:"5"`
+	Role   string `json:"role" example:"moderator"`
+}
+
+type ChannelModeratorRequest struct {
+	ChannelId uint   `json:"channelId" example:"3"`
+	Login     string `json:"login" example:"johndoe"`
+}
+
+type SetPostHiddenRequest struct {
+	PostId uint `json:"postId" example:"7"`
+	Hidden bool `json:"hidden" example:"true"`
+}
+
+type CreateComplaintRequest struct {
+	TargetType    string `json:"targetType" example:"comment"`
+	TargetId      uint   `json:"targetId" example:"42"`
+	ComplaintType string `json:"complaintType" example:"spam"`
+	Description   string `json:"description" example:"Реклама в комментариях"`
+}
+
+type ResolveComplaintRequest struct {
+	ComplaintId uint   `json:"complaintId" example:"12"`
+	Resolution  string `json:"resolution" example:"Комментарий скрыт"`
+	Action      string `json:"action" example:"hide"`
+}
+
 type CreateChannelRequest struct {
 	Name        string `json:"name" example:"BlogPoint News"`
 	Description string `json:"description" example:"More blogs here"`
@@ -150,13 +379,22 @@ type LanguageUpdateRequest struct {
 }
 
 type CreatePostRequest struct {
-	ChannelId      uint   `json:"channelId" example:"1"`
-	PreviewImageId *uint  `json:"previewImageId" example:"5"`
-	Title          string `json:"title" example:"Today's news"`
-	Content        string `json:"content" example:"Something here"`
-	Tags           []uint `json:"tags"`
-	PostImages     []uint `json:"postImages"`
-	PostFiles      []uint `json:"postFiles"`
+	ChannelId      uint       `json:"channelId" example:"1"`
+	PreviewImageId *uint      `json:"previewImageId" example:"5"`
+	Title          string     `json:"title" example:"Today's news"`
+	Content        string     `json:"content" example:"Something here"`
+	ContentFormat  string     `json:"contentFormat" example:"markdown"`
+	Tags           []uint     `json:"tags"`
+	PostImages     []uint     `json:"postImages"`
+	PostFiles      []uint     `json:"postFiles"`
+	Status         string     `json:"status" example:"draft"`
+	PublishAt      *time.Time `json:"publishAt"`
+}
+
+type SetPostStatusRequest struct {
+	PostId    uint       `json:"postId" example:"1"`
+	Status    string     `json:"status" example:"scheduled"`
+	PublishAt *time.Time `json:"publishAt"`
 }
 
 type EditPostRequest struct {
@@ -164,11 +402,30 @@ type EditPostRequest struct {
 	PreviewImageId *uint  `json:"previewImage"`
 	Title          string `json:"title" example:"Today's news"`
 	Content        string `json:"content" example:"Something here"`
+	ContentFormat  string `json:"contentFormat" example:"markdown"`
 	Tags           []uint `json:"tags"`
 	PostImages     []uint `json:"postImages"`
 	PostFiles      []uint `json:"postFiles"`
 }
 
+type MarkFeedSeenRequest struct {
+	PostIds []uint `json:"postIds" example:"12,15,17"`
+}
+
+type MarkNotificationsReadRequest struct {
+	Ids []uint `json:"ids" example:"1,2,3"`
+	All bool   `json:"all" example:"false"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceResponse `json:"preferences"`
+}
+
+type RestorePostRevisionRequest struct {
+	PostId     uint `json:"postId" example:"1"`
+	RevisionId uint `json:"revisionId" example:"2"`
+}
+
 type SetReactionRequest struct {
 	PostId   uint   `json:"postId" example:"1"`
 	Reaction string `json:"reaction" example:"like"`
@@ -179,3 +436,17 @@ type CreateCommentRequest struct {
 	Content  string `json:"content" example:"1"`
 	ParentId *uint  `json:"parentId" example:"1"`
 }
+
+// RequestUploadRequest — файл, который клиент загрузит напрямую в хранилище. Type — разрешенная категория
+// MIME-типа (image, video...), как параметр type в /api/uploadFile.
+type RequestUploadRequest struct {
+	Filename   string `json:"filename" example:"lecture.mp4"`
+	Size       int64  `json:"size" example:"734003200"`
+	MimeType   string `json:"mimeType" example:"video/mp4"`
+	Type       string `json:"type" example:"video"`
+	Visibility string `json:"visibility" example:"private"`
+}
+
+type SetFileVisibilityRequest struct {
+	Visibility string `json:"visibility" example:"private"`
+}
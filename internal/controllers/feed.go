@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/pagination"
+	"blogpoint-backend/internal/repository"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"time"
+)
+
+const (
+	feedModeLatest = "latest"
+	feedModeRanked = "ranked"
+
+	// maxSeenBatch ограничивает число постов, отмечаемых просмотренными за один запрос
+	maxSeenBatch = 100
+)
+
+// feedScoreExpr — оценка поста в режиме ranked. Логарифм рейтинга из рекомендаций складывается
+// с временем публикации: пост с рейтингом в 10 раз выше равноценен посту, опубликованному
+// на 12,5 часов позже. Оценка не зависит от текущего времени, но рейтинг меняется между запросами:
+// пост, набравший реакции после выдачи страницы, может повториться или пропасть на следующих страницах.
+// Курсор лишь задает порог оценки, поэтому страницы ranked приблизительные.
+const feedScoreExpr = "(SIGN(" + postRatingExpr + ") * LOG(GREATEST(ABS(" + postRatingExpr + "), 1)) + " +
+	"EXTRACT(EPOCH FROM posts.published_at) / 45000)::DOUBLE PRECISION"
+
+// GetFeed возвращает ленту постов из каналов, на которые подписан пользователь
+// @Summary      Лента подписок
+// @Description  Возвращает опубликованные посты из каналов, на которые подписан пользователь. В режиме latest посты идут от новых к старым по времени публикации, в режиме ranked учитывается рейтинг из рекомендаций (views + likes*3 - dislikes*2 + comments*2) вместе со свежестью поста. Рейтинг пересчитывается на каждый запрос, поэтому страницы режима ranked приблизительные: пост может повториться или выпасть при переходе по курсору. Поле isSeen показывает, отмечен ли пост просмотренным.
+// @Tags         Feed
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        mode    query     string false "Режим: latest или ranked (по умолчанию latest)"
+// @Param        unseen  query     bool   false "Только непросмотренные посты"
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]FeedPostResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/feed [get]
+func GetFeed(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	mode := c.Query("mode", feedModeLatest)
+	if mode != feedModeLatest && mode != feedModeRanked {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid feed mode",
+		})
+	}
+
+	kind := pagination.ByTime
+	if mode == feedModeRanked {
+		kind = pagination.ByScore
+	}
+
+	params, err := parsePageParams(c, kind)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	type FeedEntry struct {
+		Id          uint
+		PublishedAt time.Time
+		Score       float64
+		IsSeen      bool
+	}
+
+	subscribedPosts := ratedPostsQuery(`posts.id, posts.published_at,
+		`+feedScoreExpr+` as score,
+		EXISTS (SELECT 1 FROM seen_posts sp WHERE sp.post_id = posts.id AND sp.user_id = ?) as is_seen`, user.Id).
+		Joins("JOIN subscriptions s ON s.channel_id = posts.channel_id AND s.user_id = ?", user.Id).
+		Where("posts.status = ? AND posts.is_hidden = FALSE", models.PostStatusPublished)
+
+	// Оценка вычисляемая, поэтому курсор сравнивается с ней во внешнем запросе
+	query := repository.DB.Table("(?) as feed", subscribedPosts)
+	if c.QueryBool("unseen") {
+		query = query.Where("is_seen = FALSE")
+	}
+
+	if mode == feedModeRanked {
+		if params.Cursor != nil {
+			query = query.Where("(score, id) < (?, ?)", *params.Cursor.Score, params.Cursor.Id)
+		}
+		query = query.Order("score DESC, id DESC")
+	} else {
+		if params.Cursor != nil {
+			query = query.Where("(published_at, id) < (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+		}
+		query = query.Order("published_at DESC, id DESC")
+	}
+
+	var entries []FeedEntry
+	if err = query.Limit(params.Limit + 1).Scan(&entries).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch feed",
+		})
+	}
+
+	entries, hasMore := pagination.Trim(entries, params.Limit)
+
+	ids := make([]uint, len(entries))
+	seen := make(map[uint]bool, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.Id
+		seen[entry.Id] = entry.IsSeen
+	}
+
+	posts, err := postResponsesByIds(ids)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch posts",
+		})
+	}
+
+	feed := make([]FeedPostResponse, 0, len(posts))
+	for _, post := range posts {
+		feed = append(feed, FeedPostResponse{
+			PostResponse: post,
+			IsSeen:       seen[post.Id],
+		})
+	}
+
+	var last pagination.Cursor
+	if len(entries) > 0 {
+		lastEntry := entries[len(entries)-1]
+		if mode == feedModeRanked {
+			last = pagination.ScoreCursor(lastEntry.Score, lastEntry.Id)
+		} else {
+			last = pagination.TimeCursor(lastEntry.PublishedAt, lastEntry.Id)
+		}
+	}
+
+	return c.JSON(newPageResponse(feed, hasMore, last))
+}
+
+// MarkFeedSeen отмечает посты ленты просмотренными
+// @Summary      Отметка просмотренных постов
+// @Description  Отмечает посты просмотренными текущим пользователем, чтобы скрывать их в ленте с параметром unseen. Несуществующие и уже отмеченные посты пропускаются. За один запрос — не больше 100 постов.
+// @Tags         Feed
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      MarkFeedSeenRequest true "Id просмотренных постов"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/markFeedSeen [post]
+func MarkFeedSeen(c *fiber.Ctx) error {
+	var data MarkFeedSeenRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	if len(data.PostIds) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Post ids are required",
+		})
+	}
+	if len(data.PostIds) > maxSeenBatch {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Too many post ids",
+		})
+	}
+
+	if err := repository.DB.Exec(`INSERT INTO seen_posts (user_id, post_id)
+		SELECT ?, id FROM posts WHERE id IN ?
+		ON CONFLICT DO NOTHING`, user.Id, data.PostIds).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to mark posts as seen",
+		})
+	}
+
+	return c.JSON(MessageResponse{
+		Message: "Posts marked as seen",
+	})
+}
@@ -0,0 +1,336 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"blogpoint-backend/internal/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// privateUrlTTL — срок действия подписанной ссылки на закрытый файл
+const privateUrlTTL = 15 * time.Minute
+
+// GetFileContent отдает содержимое файла
+// @Summary      Содержимое файла
+// @Description  Отдает файл через API с поддержкой заголовка Range (один диапазон байт) для перемотки аудио и видео. Закрытый файл доступен владельцу, персоналу платформы и тем, кто может видеть пост в канале владельца, к которому он прикреплен; остальным возвращается 404. Используется, когда подписанные ссылки недоступны
+// @Tags         File
+// @Produce      octet-stream
+// @Param        id       path      int     true   "Id файла"
+// @Param        variant  query     string  false  "Вариант изображения, например preview_640"
+// @Param        Range    header    string  false  "Диапазон байт, например bytes=0-1048575"
+// @Success      200      {file}    file
+// @Success      206      {file}    file
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      416      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /api/getFileContent/{id} [get]
+func GetFileContent(c *fiber.Ctx) error {
+	fileId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || fileId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid file id"})
+	}
+
+	// О существовании недоступного закрытого файла не сообщаем
+	var file models.File
+	if err = repository.DB.First(&file, fileId).Error; err != nil || !canAccessFile(CurrentUser(c), &file) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Message: "File not found"})
+	}
+
+	object := file.Filename
+	if variant := c.Query("variant"); variant != "" {
+		var ok bool
+		if object, ok = file.Variants[variant]; !ok {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Message: "Variant not found"})
+		}
+	}
+
+	info, err := storage.StatFromMinIO(c.Context(), object)
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderContentType, info.ContentType)
+	c.Set(fiber.HeaderETag, `"`+info.ETag+`"`)
+	c.Set(fiber.HeaderLastModified, info.LastModified.UTC().Format(time.RFC1123))
+	if file.Visibility == models.FileVisibilityPrivate {
+		c.Set(fiber.HeaderCacheControl, "private, max-age=0")
+	}
+
+	start, end, partial, err := parseRange(c.Get(fiber.HeaderRange), info.Size)
+	if err != nil {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", info.Size))
+		return c.Status(fiber.StatusRequestedRangeNotSatisfiable).JSON(ErrorResponse{Message: "Range not satisfiable"})
+	}
+	if !partial {
+		start, end = -1, info.Size-1
+	}
+
+	// Тело отправляется уже после выхода из обработчика, поэтому чтение не привязываем к контексту запроса
+	reader, err := storage.OpenFromMinIO(context.Background(), object, start, end)
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	if partial {
+		c.Status(fiber.StatusPartialContent)
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, info.Size))
+		return c.SendStream(reader, int(end-start+1))
+	}
+	return c.SendStream(reader, int(info.Size))
+}
+
+// SetFileVisibility меняет видимость файла
+// @Summary      Изменение видимости файла
+// @Description  Делает файл публичным (постоянная ссылка) или закрытым (временные подписанные ссылки). Файл и его варианты переносятся в хранилище, поэтому прежние ссылки перестают действовать
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Id файла"
+// @Param        request  body      SetFileVisibilityRequest  true  "Видимость: public или private"
+// @Success      200      {object}  DataResponse[FileResponse]
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /api/setFileVisibility/{id} [patch]
+func SetFileVisibility(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	fileId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || fileId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid file id"})
+	}
+
+	var data SetFileVisibilityRequest
+	if err = json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+	visibility, ok := parseVisibility(data.Visibility)
+	if !ok || data.Visibility == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid visibility"})
+	}
+
+	var file models.File
+	if err = repository.DB.First(&file, fileId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Message: "File not found"})
+	}
+	if file.OwnerId != user.Id {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Message: "You are not the owner of this file"})
+	}
+
+	if file.Visibility == visibility {
+		return c.JSON(DataResponse[FileResponse]{Data: newFileResponse(file)})
+	}
+
+	// Сначала копируем объекты на новое место и обновляем запись, а старые удаляем в последнюю очередь,
+	// чтобы при ошибке запись не ссылалась на отсутствующие объекты
+	moved := file
+	moved.Visibility = visibility
+	moved.Filename = objectPrefix(visibility) + strings.TrimPrefix(file.Filename, storage.PrivatePrefix)
+	moved.Variants = make(map[string]string, len(file.Variants))
+	for name, object := range file.Variants {
+		moved.Variants[name] = objectPrefix(visibility) + strings.TrimPrefix(object, storage.PrivatePrefix)
+	}
+
+	copied := make([]string, 0, len(file.Variants)+1)
+	copyObject := func(src, dst string) error {
+		if err := storage.CopyInMinIO(c.Context(), src, dst); err != nil {
+			return err
+		}
+		copied = append(copied, dst)
+		return nil
+	}
+
+	err = copyObject(file.Filename, moved.Filename)
+	for name, object := range file.Variants {
+		if err != nil {
+			break
+		}
+		err = copyObject(object, moved.Variants[name])
+	}
+	if err == nil {
+		err = repository.DB.Model(&moved).Select("Filename", "Visibility", "Variants").Updates(&moved).Error
+		if err != nil {
+			err = fiber.NewError(fiber.StatusInternalServerError, "Failed to update file")
+		}
+	}
+	if err != nil {
+		if len(copied) > 0 {
+			_ = storage.DeleteFromMinIO(c.Context(), copied[0], copied[1:]...)
+		}
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	if err = storage.DeleteFromMinIO(c.Context(), file.Filename, file.VariantObjects()...); err != nil {
+		log.Printf("Не удалось удалить прежние объекты файла %d: %v", file.Id, err)
+	}
+
+	return c.JSON(DataResponse[FileResponse]{
+		Data:    newFileResponse(moved),
+		Message: "File visibility updated",
+	})
+}
+
+// newPrivateFileResponse собирает ответ для закрытого файла с временными ссылками. Если подписывать ссылки
+// нельзя, ссылки ведут на /api/getFileContent.
+func newPrivateFileResponse(file models.File) FileResponse {
+	expiresAt := time.Now().Add(privateUrlTTL)
+
+	url, err := storage.PresignDownload(file.Filename, privateUrlTTL)
+	if err != nil {
+		if !errors.Is(err, storage.ErrPresignUnavailable) {
+			log.Printf("Не удалось подписать ссылку на файл %d: %v", file.Id, err)
+		}
+		return newProxiedFileResponse(file)
+	}
+
+	var variants map[string]string
+	if len(file.Variants) > 0 {
+		variants = make(map[string]string, len(file.Variants))
+		for name, object := range file.Variants {
+			if variants[name], err = storage.PresignDownload(object, privateUrlTTL); err != nil {
+				return newProxiedFileResponse(file)
+			}
+		}
+	}
+
+	return FileResponse{
+		Id:         file.Id,
+		Url:        url,
+		Visibility: models.FileVisibilityPrivate,
+		Variants:   variants,
+		ExpiresAt:  &expiresAt,
+	}
+}
+
+// newProxiedFileResponse собирает ответ со ссылками на отдачу файла через API
+func newProxiedFileResponse(file models.File) FileResponse {
+	url := "/api/getFileContent/" + strconv.FormatUint(uint64(file.Id), 10)
+
+	var variants map[string]string
+	if len(file.Variants) > 0 {
+		variants = make(map[string]string, len(file.Variants))
+		for name := range file.Variants {
+			variants[name] = url + "?variant=" + name
+		}
+	}
+
+	return FileResponse{
+		Id:         file.Id,
+		Url:        url,
+		Visibility: models.FileVisibilityPrivate,
+		Variants:   variants,
+	}
+}
+
+// canAccessFile разрешает доступ к публичному файлу всем, к закрытому — владельцу, персоналу платформы
+// и тем, кто может видеть пост в канале владельца, к которому файл прикреплен (превью, изображение или вложение).
+// Пост в чужом канале доступа не открывает: иначе закрытый файл стал бы доступен читателям канала,
+// владелец которого файл не прикреплял.
+func canAccessFile(user *models.User, file *models.File) bool {
+	if file.Visibility != models.FileVisibilityPrivate {
+		return true
+	}
+	if user != nil && (file.OwnerId == user.Id || isStaff(user)) {
+		return true
+	}
+
+	var posts []models.Post
+	repository.DB.
+		Joins("JOIN channels ON channels.id = posts.channel_id").
+		Where("channels.owner_id = ?", file.OwnerId).
+		Where(repository.DB.
+			Where("posts.preview_image_id = ?", file.Id).
+			Or("posts.id IN (SELECT post_id FROM post_images WHERE file_id = ?)", file.Id).
+			Or("posts.id IN (SELECT post_id FROM post_files WHERE file_id = ?)", file.Id)).
+		Find(&posts)
+	for i := range posts {
+		if canViewPost(user, &posts[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVisibility проверяет видимость файла; пустая строка означает публичный файл
+func parseVisibility(value string) (string, bool) {
+	switch value {
+	case "", models.FileVisibilityPublic:
+		return models.FileVisibilityPublic, true
+	case models.FileVisibilityPrivate:
+		return models.FileVisibilityPrivate, true
+	}
+	return "", false
+}
+
+// objectPrefix возвращает каталог хранилища для файлов с такой видимостью
+func objectPrefix(visibility string) string {
+	if visibility == models.FileVisibilityPrivate {
+		return storage.PrivatePrefix
+	}
+	return ""
+}
+
+// parseRange разбирает заголовок Range с одним диапазоном байт: bytes=a-b, bytes=a- или bytes=-n.
+// partial == false, если заголовка нет или он не поддерживается (несколько диапазонов, другие единицы):
+// тогда отдается весь файл. Ошибка — диапазон за пределами файла.
+func parseRange(header string, size int64) (start, end int64, partial bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, nil
+	}
+
+	errUnsatisfiable := errors.New("range not satisfiable")
+
+	if first == "" {
+		// Последние n байт
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, errUnsatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size - 1, true, nil
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, nil
+	}
+	if start >= size {
+		return 0, 0, false, errUnsatisfiable
+	}
+
+	end = size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, nil
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+	return start, end, true, nil
+}
@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"blogpoint-backend/internal/media"
 	"blogpoint-backend/internal/models"
 	"blogpoint-backend/internal/repository"
 	"blogpoint-backend/internal/storage"
+	"bytes"
+	"context"
+	"errors"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"io"
 	"net/http"
@@ -21,46 +24,28 @@ func GenerateUniqueFilename(filename string) string {
 
 // UploadFile обрабатывает загрузку файла
 // @Summary      Загрузка файла
-// @Description  Загружает файл и возвращает его Id и URL
+// @Description  Загружает файл и возвращает его Id и URL. Из изображений JPEG, PNG и WebP удаляются метаданные (EXIF, XMP), PNG и WebP сохраняются в PNG или в WebP без потерь (media.webp), для них создаются варианты preview_640 и preview_1280 (только меньше оригинала), их URL возвращаются в variants
 // @Tags         File
 // @Security     ApiKeyAuth
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        type  query     string false "Тип файла"
-// @Param        file  formData  file true "Файл для загрузки"
-// @Success      200   {object}  FileResponse
-// @Failure      400   {object}  ErrorResponse
-// @Failure      500   {object}  ErrorResponse
+// @Param        type        query     string false "Тип файла"
+// @Param        visibility  query     string false "Видимость файла: public (по умолчанию) или private"
+// @Param        file        formData  file true "Файл для загрузки"
+// @Success      200         {object}  FileResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      413         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
 // @Router       /api/uploadFile [post]
 func UploadFile(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
+	user := CurrentUser(c)
 
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
+	visibility, ok := parseVisibility(c.Query("visibility"))
 	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid visibility"})
 	}
 
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid user Id",
-		})
-	}
-
-	filename, mimeType, err := ProcessUpload(c, c.Query("type", ""))
+	file, err := ProcessUpload(c, c.Query("type", ""), media.PresetPreview, visibility)
 
 	if err != nil {
 		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
@@ -68,68 +53,154 @@ func UploadFile(c *fiber.Ctx) error {
 		})
 	}
 
-	file := models.File{
-		OwnerId:  uint(userId),
-		Filename: filename,
-		MimeType: mimeType,
-	}
+	file.OwnerId = user.Id
 
 	if err = repository.DB.Create(&file).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving file to DB"})
 	}
 
-	url := storage.GetUrl(filename)
-
-	fileResponse := FileResponse{
-		Id:  file.Id,
-		Url: url,
-	}
-
 	return c.JSON(DataResponse[FileResponse]{
-		Data:    fileResponse,
+		Data:    newFileResponse(file),
 		Message: "Файл загружен",
 	})
 }
 
-// ProcessUpload обрабатывает загрузку файла в MinIo
-func ProcessUpload(c *fiber.Ctx, allowedType string) (string, string, error) {
+// ProcessUpload обрабатывает загрузку файла в MinIo. Изображения перед загрузкой проходят через media.Process:
+// метаданные удаляются, а варианты из набора preset сохраняются рядом с оригиналом. Закрытые файлы
+// сохраняются в storage.PrivatePrefix. Возвращает еще не сохраненную в базе запись файла без владельца.
+func ProcessUpload(c *fiber.Ctx, allowedType string, preset media.Preset, visibility string) (models.File, error) {
 	// Читаем файл из запроса
 	file, err := c.FormFile("file")
 	if err != nil {
-		return "", "", fiber.NewError(fiber.StatusBadRequest, "Error receiving file")
+		return models.File{}, fiber.NewError(fiber.StatusBadRequest, "Error receiving file")
 	}
 
 	// Открываем файл
 	src, err := file.Open()
 	if err != nil {
-		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Error opening file")
+		return models.File{}, fiber.NewError(fiber.StatusInternalServerError, "Error opening file")
 	}
 	defer src.Close()
 
 	// Определяем MIME-тип
 	buffer := make([]byte, 512)
 	if _, err := src.Read(buffer); err != nil {
-		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Error reading file")
+		return models.File{}, fiber.NewError(fiber.StatusInternalServerError, "Error reading file")
 	}
 	// Возвращаемся в начало файла, так как Read сместил указатель
 	if _, err := src.Seek(0, io.SeekStart); err != nil {
-		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Error resetting file pointer")
+		return models.File{}, fiber.NewError(fiber.StatusInternalServerError, "Error resetting file pointer")
 	}
 	mimeType := http.DetectContentType(buffer)
 
 	if allowedType != "" && allowedType != strings.Split(mimeType, "/")[0] {
-		return "", "", fiber.NewError(fiber.StatusBadRequest, "File type is not allowed")
+		return models.File{}, fiber.NewError(fiber.StatusBadRequest, "File type is not allowed")
 	}
 
 	// Генерируем уникальное имя файла
-	uniqueFilename := strings.Split(mimeType, "/")[0] + "/" + GenerateUniqueFilename(file.Filename)
+	uniqueFilename := objectPrefix(visibility) + strings.Split(mimeType, "/")[0] + "/" + GenerateUniqueFilename(file.Filename)
+
+	if media.Supported(mimeType) {
+		// Метаданные удаляются только при перекодировании, поэтому необработанное изображение не сохраняем
+		if file.Size > media.MaxFileSize {
+			return models.File{}, fiber.NewError(fiber.StatusRequestEntityTooLarge, "Image is too large to process")
+		}
+		stored, err := uploadImage(c, src, uniqueFilename, mimeType, preset)
+		if err != nil {
+			return models.File{}, err
+		}
+		stored.Visibility = visibility
+		return stored, nil
+	}
 
 	// Загружаем файл в MinIO
 	if err = storage.UploadToMinIO(c.Context(), uniqueFilename, src, file.Size, mimeType); err != nil {
-		return "", "", err
+		return models.File{}, err
+	}
+
+	return models.File{
+		Filename:   uniqueFilename,
+		MimeType:   mimeType,
+		Visibility: visibility,
+		Variants:   map[string]string{},
+	}, nil
+}
+
+// uploadImage загружает изображение без метаданных и его варианты
+func uploadImage(c *fiber.Ctx, src io.Reader, filename string, mimeType string, preset media.Preset) (models.File, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return models.File{}, fiber.NewError(fiber.StatusInternalServerError, "Error reading file")
+	}
+
+	return storeImage(c.Context(), data, filename, mimeType, preset)
+}
+
+// storeImage сохраняет изображение без метаданных и его варианты. Если media.Process сменил формат, объект
+// получает расширение нового формата: image/<uuid>.png -> image/<uuid>.webp. Имя варианта получается из имени
+// сохраненного оригинала: image/<uuid>.jpg -> image/<uuid>_preview_640.jpg. Если файл уже лежит в хранилище
+// (загрузка напрямую), он перезаписывается очищенной копией, а если сменилось имя, остается на месте
+// и удалять его должен вызывающий.
+func storeImage(ctx context.Context, data []byte, filename string, mimeType string, preset media.Preset) (models.File, error) {
+	result, err := media.Process(data, mimeType, preset)
+	switch {
+	case errors.Is(err, media.ErrInvalidImage):
+		return models.File{}, fiber.NewError(fiber.StatusBadRequest, "Invalid image")
+	case errors.Is(err, media.ErrTooLarge):
+		return models.File{}, fiber.NewError(fiber.StatusRequestEntityTooLarge, "Image is too large to process")
+	case err != nil:
+		return models.File{}, fiber.NewError(fiber.StatusInternalServerError, "Error processing image")
+	}
+
+	stored := models.File{
+		Filename: filename,
+		MimeType: mimeType,
+		Variants: make(map[string]string, len(result.Variants)),
+	}
+
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	original := result.Original
+	if original.MimeType != mimeType {
+		stored.Filename, stored.MimeType = base+original.Ext, original.MimeType
+	}
+	if err = storage.UploadToMinIO(ctx, stored.Filename, bytes.NewReader(original.Data), int64(len(original.Data)), stored.MimeType); err != nil {
+		return models.File{}, err
+	}
+
+	for _, variant := range result.Variants {
+		object := base + "_" + variant.Name + variant.Ext
+		if err = storage.UploadToMinIO(ctx, object, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.MimeType); err != nil {
+			// Не оставляем в хранилище оригинал и уже загруженные варианты
+			_ = storage.DeleteFromMinIO(ctx, stored.Filename, stored.VariantObjects()...)
+			return models.File{}, err
+		}
+		stored.Variants[variant.Name] = object
 	}
 
-	return uniqueFilename, mimeType, nil
+	return stored, nil
+}
+
+// newFileResponse собирает ответ с URL файла и URL его вариантов. Для закрытого файла это временные
+// подписанные ссылки, поэтому ответ можно отдавать только тем, кому файл доступен.
+func newFileResponse(file models.File) FileResponse {
+	if file.Visibility == models.FileVisibilityPrivate {
+		return newPrivateFileResponse(file)
+	}
+
+	var variants map[string]string
+	if len(file.Variants) > 0 {
+		variants = make(map[string]string, len(file.Variants))
+		for name, object := range file.Variants {
+			variants[name] = storage.GetUrl(object)
+		}
+	}
+
+	return FileResponse{
+		Id:         file.Id,
+		Url:        storage.GetUrl(file.Filename),
+		Visibility: models.FileVisibilityPublic,
+		Variants:   variants,
+	}
 }
 
 // DeleteFile обрабатывает удаление файла
@@ -147,32 +218,7 @@ func ProcessUpload(c *fiber.Ctx, allowedType string) (string, string, error) {
 // @Failure      500  {object}  ErrorResponse
 // @Router       /api/deleteFile/{id} [delete]
 func DeleteFile(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	fileId, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || fileId == 0 {
@@ -190,14 +236,14 @@ func DeleteFile(c *fiber.Ctx) error {
 		})
 	}
 
-	if file.OwnerId != uint(userId) {
+	if file.OwnerId != user.Id {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this file",
 		})
 	}
 
-	if err = storage.DeleteFromMinIO(c.Context(), file.Filename); err != nil {
+	if err = storage.DeleteFromMinIO(c.Context(), file.Filename, file.VariantObjects()...); err != nil {
 		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{
 			Message: err.(*fiber.Error).Error(),
 		})
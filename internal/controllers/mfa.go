@@ -0,0 +1,456 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+	"image/png"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// mfaTokenTTL — сколько действует токен второго шага входа
+	mfaTokenTTL  = 5 * time.Minute
+	mfaTokenType = "mfa"
+
+	// Коды TOTP по RFC 6238: 6 цифр, шаг 30 секунд. Из-за расхождения часов принимается и соседний шаг.
+	totpPeriod = 30
+	totpSkew   = 1
+
+	recoveryCodesCount = 10
+	qrCodeSize         = 256
+)
+
+// LoginMfa завершает вход кодом второго фактора
+// @Summary      Второй шаг входа
+// @Description  Обменивает mfaToken из ответа /api/login и код TOTP (или неиспользованный код восстановления) на сессию. Неверные коды учитываются в блокировке входа так же, как неверные пароли
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      LoginMfaRequest true "Токен и код"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/loginMfa [post]
+func LoginMfa(c *fiber.Ctx) error {
+	var data LoginMfaRequest
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	userId, ok := parseMfaToken(data.MfaToken)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
+			Message: "Invalid or expired token",
+		})
+	}
+
+	var user models.User
+	if err := repository.DB.First(&user, userId).Error; err != nil || !user.TotpEnabled {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
+			Message: "Invalid or expired token",
+		})
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return accountLocked(c, time.Until(*user.LockedUntil))
+	}
+
+	if !checkSecondFactor(&user, data.Code) {
+		lock, err := registerFailedLogin(&user)
+		if err != nil {
+			log.Printf("Не удалось учесть неудачный вход пользователя %d: %v", user.Id, err)
+		}
+		if lock > 0 {
+			log.Printf("Аккаунт %d заблокирован на %s после неверных кодов второго фактора (ip %s)", user.Id, lock, c.IP())
+			return accountLocked(c, lock)
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid code",
+		})
+	}
+
+	return completeLogin(c, &user)
+}
+
+// SetupTotp начинает подключение двухфакторной аутентификации
+// @Summary      Подключение TOTP
+// @Description  Создает новый секрет TOTP и возвращает его вместе с URI otpauth:// и QR-кодом для приложения-аутентификатора. Вход с кодом включается только после подтверждения через /api/confirmTotp
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  DataResponse[TotpSetupResponse]
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/setupTotp [post]
+func SetupTotp(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	if user.TotpEnabled {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Message: "Two-factor authentication is already enabled",
+		})
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      mail.BrandName,
+		AccountName: user.Login,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return err
+	}
+
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err = png.Encode(&buffer, image); err != nil {
+		return err
+	}
+
+	if err = repository.DB.Model(user).UpdateColumns(map[string]interface{}{
+		"totp_secret":    key.Secret(),
+		"totp_last_step": 0,
+	}).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to start two-factor setup",
+		})
+	}
+
+	return c.JSON(DataResponse[TotpSetupResponse]{
+		Data: TotpSetupResponse{
+			Secret: key.Secret(),
+			Uri:    key.URL(),
+			QrCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buffer.Bytes()),
+		},
+	})
+}
+
+// ConfirmTotp включает двухфакторную аутентификацию
+// @Summary      Подтверждение TOTP
+// @Description  Проверяет код из приложения-аутентификатора, включает вход с кодом и возвращает коды восстановления. Коды показываются один раз
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      CodeRequest true "Код TOTP"
+// @Success      200   {object}  DataResponse[RecoveryCodesResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/confirmTotp [post]
+func ConfirmTotp(c *fiber.Ctx) error {
+	var data CodeRequest
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	if user.TotpEnabled {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+			Message: "Two-factor authentication is already enabled",
+		})
+	}
+
+	if user.TotpSecret == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Two-factor setup was not started",
+		})
+	}
+
+	if !checkTotpCode(user, data.Code) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid code",
+		})
+	}
+
+	var codes []string
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).UpdateColumn("totp_enabled", true).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.Id)
+		return err
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to enable two-factor authentication",
+		})
+	}
+
+	return c.JSON(DataResponse[RecoveryCodesResponse]{
+		Data:    RecoveryCodesResponse{Codes: codes},
+		Message: "Two-factor authentication enabled",
+	})
+}
+
+// DisableTotp отключает двухфакторную аутентификацию
+// @Summary      Отключение TOTP
+// @Description  Отключает вход с кодом и удаляет коды восстановления. Требуется текущий код TOTP или код восстановления
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      CodeRequest true "Код TOTP или код восстановления"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/disableTotp [post]
+func DisableTotp(c *fiber.Ctx) error {
+	var data CodeRequest
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	if !user.TotpEnabled {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Two-factor authentication is not enabled",
+		})
+	}
+
+	if !checkSecondFactor(user, data.Code) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid code",
+		})
+	}
+
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).UpdateColumns(map[string]interface{}{
+			"totp_secret":    nil,
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.RecoveryCode{}, "user_id = ?", user.Id).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to disable two-factor authentication",
+		})
+	}
+
+	return c.JSON(MessageResponse{
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes выдает новые коды восстановления
+// @Summary      Новые коды восстановления
+// @Description  Заменяет все коды восстановления новыми. Требуется текущий код TOTP
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      CodeRequest true "Код TOTP"
+// @Success      200   {object}  DataResponse[RecoveryCodesResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/regenerateRecoveryCodes [post]
+func RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var data CodeRequest
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	if !user.TotpEnabled {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Two-factor authentication is not enabled",
+		})
+	}
+
+	if !checkTotpCode(user, data.Code) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid code",
+		})
+	}
+
+	var codes []string
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, user.Id)
+		return err
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to generate recovery codes",
+		})
+	}
+
+	return c.JSON(DataResponse[RecoveryCodesResponse]{
+		Data: RecoveryCodesResponse{Codes: codes},
+	})
+}
+
+// completeLogin сбрасывает счетчик неудачных попыток и открывает сессию
+func completeLogin(c *fiber.Ctx, user *models.User) error {
+	resetFailedLogins(user)
+
+	if _, err := startSession(c, user.Id); err != nil {
+		c.Status(fiber.StatusInternalServerError)
+		return c.JSON(ErrorResponse{
+			Message: "Could not login",
+		})
+	}
+
+	return c.JSON(MessageResponse{
+		Message: "Successful authorization",
+	})
+}
+
+// issueMfaToken выдает токен второго шага входа: пароль проверен, ждем код.
+// Токен подписан тем же секретом, что и access токен, но не содержит сессии, поэтому authenticate его не примет.
+func issueMfaToken(userId uint) (string, error) {
+	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": strconv.Itoa(int(userId)),
+		"typ": mfaTokenType,
+		"exp": time.Now().Add(mfaTokenTTL).Unix(),
+	})
+	return claims.SignedString([]byte(authConfig.JWTSecret))
+}
+
+func parseMfaToken(value string) (uint, bool) {
+	token, err := jwt.ParseWithClaims(value, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(authConfig.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, false
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if typ, _ := claims["typ"].(string); typ != mfaTokenType {
+		return 0, false
+	}
+
+	strId, _ := claims["sub"].(string)
+	userId, err := strconv.ParseUint(strId, 10, 32)
+	if err != nil || userId == 0 {
+		return 0, false
+	}
+
+	return uint(userId), true
+}
+
+// checkSecondFactor принимает код TOTP из 6 цифр или неиспользованный код восстановления
+func checkSecondFactor(user *models.User, code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) == 6 && strings.Trim(code, "0123456789") == "" {
+		return checkTotpCode(user, code)
+	}
+	return useRecoveryCode(user.Id, code)
+}
+
+// checkTotpCode сверяет код с секретом пользователя. Принятый шаг запоминается,
+// поэтому один и тот же код нельзя использовать дважды.
+func checkTotpCode(user *models.User, code string) bool {
+	if user.TotpSecret == nil {
+		return false
+	}
+
+	code = strings.TrimSpace(code)
+	current := time.Now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= user.TotpLastStep {
+			continue
+		}
+
+		expected, err := totp.GenerateCodeCustom(*user.TotpSecret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period: totpPeriod,
+		})
+		if err != nil {
+			return false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+
+		// Условие на шаг защищает от параллельного входа с тем же кодом
+		result := repository.DB.Model(&models.User{}).
+			Where("id = ? AND totp_last_step < ?", user.Id, step).
+			UpdateColumn("totp_last_step", step)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return false
+		}
+
+		user.TotpLastStep = step
+		return true
+	}
+
+	return false
+}
+
+// useRecoveryCode погашает код восстановления. Регистр и дефисы при вводе не важны.
+func useRecoveryCode(userId uint, code string) bool {
+	code = normalizeRecoveryCode(code)
+	if code == "" {
+		return false
+	}
+
+	result := repository.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, hashRefreshSecret(code)).
+		UpdateColumn("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// replaceRecoveryCodes удаляет старые коды восстановления и создает новые. Возвращает коды в виде XXXX-XXXX.
+func replaceRecoveryCodes(tx *gorm.DB, userId uint) ([]string, error) {
+	if err := tx.Delete(&models.RecoveryCode{}, "user_id = ?", userId).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	entries := make([]models.RecoveryCode, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, err := randomCode(8)
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code[:4]+"-"+code[4:])
+		entries = append(entries, models.RecoveryCode{
+			UserId:   userId,
+			CodeHash: hashRefreshSecret(code),
+		})
+	}
+
+	if err := tx.Create(&entries).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+	"time"
+)
+
+const (
+	userLocalsKey     = "user"
+	sessionLocalsKey  = "session"
+	authCheckedLocals = "authChecked"
+)
+
+// OptionalAuth пытается авторизовать пользователя по JWT cookie.
+// Запрос без токена или с невалидным токеном проходит дальше как анонимный.
+func OptionalAuth(c *fiber.Ctx) error {
+	authenticate(c)
+	return c.Next()
+}
+
+// RequireAuth пропускает дальше только авторизованных пользователей
+func RequireAuth(c *fiber.Ctx) error {
+	if authenticate(c) == nil {
+		c.Status(fiber.StatusUnauthorized)
+		return c.JSON(ErrorResponse{
+			Message: "Unauthenticated",
+		})
+	}
+	return c.Next()
+}
+
+// CurrentUser возвращает пользователя, сохраненного middleware, или nil для анонимного запроса
+func CurrentUser(c *fiber.Ctx) *models.User {
+	user, _ := c.Locals(userLocalsKey).(*models.User)
+	return user
+}
+
+// CurrentSession возвращает серверную сессию, к которой привязан access токен запроса
+func CurrentSession(c *fiber.Ctx) *models.Session {
+	session, _ := c.Locals(sessionLocalsKey).(*models.Session)
+	return session
+}
+
+// authenticate разбирает JWT cookie и загружает пользователя. Результат кешируется в c.Locals,
+// поэтому повторный вызов в рамках одного запроса не обращается к базе.
+func authenticate(c *fiber.Ctx) *models.User {
+	if checked, _ := c.Locals(authCheckedLocals).(bool); checked {
+		return CurrentUser(c)
+	}
+	c.Locals(authCheckedLocals, true)
+
+	cookie := c.Cookies(accessCookieName)
+	if cookie == "" {
+		return nil
+	}
+
+	token, err := jwt.ParseWithClaims(cookie, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(authConfig.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	strId, ok := claims["iss"].(string)
+	if !ok {
+		return nil
+	}
+
+	userId, err := strconv.ParseUint(strId, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	strSessionId, ok := claims["sid"].(string)
+	if !ok {
+		return nil
+	}
+
+	sessionId, err := strconv.ParseUint(strSessionId, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	// Access токен действителен только пока жива серверная сессия, поэтому Logout отзывает его сразу
+	var session models.Session
+	if err = repository.DB.First(&session, "id = ? AND user_id = ?", sessionId, userId).Error; err != nil {
+		return nil
+	}
+
+	if !isSessionActive(&session) {
+		return nil
+	}
+
+	if now := time.Now(); now.Sub(session.LastSeenAt) > lastSeenInterval {
+		repository.DB.Model(&session).UpdateColumn("last_seen_at", now)
+		session.LastSeenAt = now
+	}
+
+	var user models.User
+	if err = repository.DB.Preload("Role").First(&user, userId).Error; err != nil {
+		return nil
+	}
+
+	c.Locals(userLocalsKey, &user)
+	c.Locals(sessionLocalsKey, &session)
+	return &user
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/pagination"
+	"blogpoint-backend/internal/repository"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"time"
+)
+
+// AddChannelModerator назначает пользователя модератором канала
+// @Summary      Назначение модератора канала
+// @Description  Назначает пользователя с указанным логином модератором канала. Доступно владельцу канала, модераторам и администраторам платформы.
+// @Tags         Moderation
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      ChannelModeratorRequest true "Id канала и логин пользователя"
+// @Success      200   {object}  DataResponse[ChannelModeratorResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/addChannelModerator [post]
+func AddChannelModerator(c *fiber.Ctx) error {
+	var data ChannelModeratorRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	var channel models.Channel
+	if err := repository.DB.First(&channel, data.ChannelId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	if !canManageChannel(user, &channel) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not the owner of this channel",
+		})
+	}
+
+	var moderator models.User
+	if err := repository.DB.Where("login = ?", data.Login).First(&moderator).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "User not found",
+		})
+	}
+
+	if moderator.Id == channel.OwnerId {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Channel owner cannot be a moderator",
+		})
+	}
+
+	channelModerator := models.ChannelModerator{
+		ChannelId:   channel.Id,
+		ModeratorId: moderator.Id,
+		AssignedAt:  time.Now(),
+	}
+
+	result := repository.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&channelModerator)
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to add moderator",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "User is already a moderator of this channel",
+		})
+	}
+
+	logModeration(user, channel.Id, actionAddModerator, "user", moderator.Id, moderator.Login)
+
+	return c.JSON(DataResponse[ChannelModeratorResponse]{
+		Data: ChannelModeratorResponse{
+			Id:         moderator.Id,
+			Login:      moderator.Login,
+			AssignedAt: channelModerator.AssignedAt,
+		},
+		Message: "Moderator added successfully",
+	})
+}
+
+// RemoveChannelModerator снимает пользователя с модерации канала
+// @Summary      Снятие модератора канала
+// @Description  Снимает пользователя с модерации канала. Доступно владельцу канала, модераторам и администраторам платформы, а также самому модератору.
+// @Tags         Moderation
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        channelId  path      int true "Id канала"
+// @Param        userId     path      int true "Id модератора"
+// @Success      200        {object}  MessageResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      403        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/removeChannelModerator/{channelId}/{userId} [delete]
+func RemoveChannelModerator(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	channelId, err := strconv.ParseUint(c.Params("channelId"), 10, 64)
+	if err != nil || channelId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid channel Id"})
+	}
+
+	moderatorId, err := strconv.ParseUint(c.Params("userId"), 10, 64)
+	if err != nil || moderatorId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid user Id"})
+	}
+
+	var channel models.Channel
+	if err = repository.DB.First(&channel, channelId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	// Модератор может сам отказаться от своих прав
+	if !canManageChannel(user, &channel) && user.Id != uint(moderatorId) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not the owner of this channel",
+		})
+	}
+
+	result := repository.DB.Where("channel_id = ? AND moderator_id = ?", channelId, moderatorId).
+		Delete(&models.ChannelModerator{})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to remove moderator",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "User is not a moderator of this channel",
+		})
+	}
+
+	logModeration(user, channel.Id, actionRemoveModerator, "user", uint(moderatorId), "")
+
+	return c.JSON(MessageResponse{
+		Message: "Moderator removed successfully",
+	})
+}
+
+// GetChannelModerators возвращает модераторов канала
+// @Summary      Модераторы канала
+// @Description  Возвращает список модераторов канала. Доступно тем, кто может модерировать канал.
+// @Tags         Moderation
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int true "Id канала"
+// @Success      200  {object}  DataResponse[[]ChannelModeratorResponse]
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/getChannelModerators/{id} [get]
+func GetChannelModerators(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	channelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || channelId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid channel Id"})
+	}
+
+	var channel models.Channel
+	if err = repository.DB.First(&channel, channelId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	if !canModerateChannel(user, &channel) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not allowed to moderate this channel",
+		})
+	}
+
+	moderators := make([]ChannelModeratorResponse, 0)
+	if err = repository.DB.
+		Table("channel_moderators").
+		Select("users.id, users.login, channel_moderators.assigned_at").
+		Joins("JOIN users ON users.id = channel_moderators.moderator_id").
+		Where("channel_moderators.channel_id = ?", channelId).
+		Order("channel_moderators.assigned_at").
+		Scan(&moderators).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch moderators",
+		})
+	}
+
+	return c.JSON(DataResponse[[]ChannelModeratorResponse]{
+		Data: moderators,
+	})
+}
+
+// GetModerationLog возвращает журнал модерации канала
+// @Summary      Журнал модерации
+// @Description  Возвращает действия модераторов в канале, новые сначала, с курсорной пагинацией. Доступно владельцу канала, модераторам и администраторам платформы.
+// @Tags         Moderation
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id      path      int    true  "Id канала"
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]ModerationLogResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      403     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/getModerationLog/{id} [get]
+func GetModerationLog(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	channelId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || channelId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid channel Id"})
+	}
+
+	params, err := parsePageParams(c, pagination.ByTime)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	var channel models.Channel
+	if err = repository.DB.First(&channel, channelId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	if !canManageChannel(user, &channel) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not the owner of this channel",
+		})
+	}
+
+	query := repository.DB.Where("channel_id = ?", channelId)
+	if params.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
+
+	var entries []models.ModerationLog
+	if err = query.Order("created_at DESC, id DESC").Limit(params.Limit + 1).Find(&entries).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch moderation log",
+		})
+	}
+
+	entries, hasMore := pagination.Trim(entries, params.Limit)
+
+	response := make([]ModerationLogResponse, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, ModerationLogResponse{
+			Id:         entry.Id,
+			ActorId:    entry.ActorId,
+			Action:     entry.Action,
+			TargetType: entry.TargetType,
+			TargetId:   entry.TargetId,
+			Details:    entry.Details,
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+
+	var last pagination.Cursor
+	if len(entries) > 0 {
+		lastEntry := entries[len(entries)-1]
+		last = pagination.TimeCursor(lastEntry.CreatedAt, lastEntry.Id)
+	}
+
+	return c.JSON(newPageResponse(response, hasMore, last))
+}
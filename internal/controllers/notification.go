@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/notify"
+	"blogpoint-backend/internal/pagination"
+	"blogpoint-backend/internal/repository"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+)
+
+// maxNotificationsBatch ограничивает число уведомлений, отмечаемых прочитанными за один запрос
+const maxNotificationsBatch = 100
+
+// GetNotifications возвращает уведомления пользователя
+// @Summary      Список уведомлений
+// @Description  Возвращает уведомления текущего пользователя, новые сначала, с курсорной пагинацией. Типы: comment_reply — ответ на комментарий, new_subscriber — новый подписчик канала, new_post — новый пост в канале из подписок.
+// @Tags         Notification
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        unread  query     bool   false "Только непрочитанные"
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]NotificationResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/getNotifications [get]
+func GetNotifications(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	params, err := parsePageParams(c, pagination.ByTime)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	query := repository.DB.Where("user_id = ?", user.Id)
+	if c.QueryBool("unread") {
+		query = query.Where("is_read = FALSE")
+	}
+	if params.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
+
+	var notifications []models.Notification
+	if err = query.Order("created_at DESC, id DESC").Limit(params.Limit + 1).Find(&notifications).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch notifications",
+		})
+	}
+
+	notifications, hasMore := pagination.Trim(notifications, params.Limit)
+
+	response := make([]NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		response = append(response, NotificationResponse{
+			Id:        notification.Id,
+			Type:      notification.Type,
+			ActorId:   notification.ActorId,
+			ChannelId: notification.ChannelId,
+			PostId:    notification.PostId,
+			CommentId: notification.CommentId,
+			Data:      notification.Data,
+			IsRead:    notification.IsRead,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	var last pagination.Cursor
+	if len(notifications) > 0 {
+		lastNotification := notifications[len(notifications)-1]
+		last = pagination.TimeCursor(lastNotification.CreatedAt, lastNotification.Id)
+	}
+
+	return c.JSON(newPageResponse(response, hasMore, last))
+}
+
+// GetUnreadNotificationsCount возвращает число непрочитанных уведомлений
+// @Summary      Число непрочитанных уведомлений
+// @Description  Возвращает количество непрочитанных уведомлений текущего пользователя
+// @Tags         Notification
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  DataResponse[int64]
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/getUnreadNotificationsCount [get]
+func GetUnreadNotificationsCount(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	var count int64
+	if err := repository.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = FALSE", user.Id).
+		Count(&count).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to count notifications",
+		})
+	}
+
+	return c.JSON(DataResponse[int64]{
+		Data: count,
+	})
+}
+
+// MarkNotificationsRead отмечает уведомления прочитанными
+// @Summary      Отметка уведомлений прочитанными
+// @Description  Отмечает прочитанными перечисленные уведомления (не больше 100 за запрос) или, при all = true, все уведомления пользователя. Чужие уведомления пропускаются.
+// @Tags         Notification
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      MarkNotificationsReadRequest true "Id уведомлений или признак all"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/markNotificationsRead [patch]
+func MarkNotificationsRead(c *fiber.Ctx) error {
+	var data MarkNotificationsReadRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	query := repository.DB.Model(&models.Notification{}).Where("user_id = ? AND is_read = FALSE", user.Id)
+	if !data.All {
+		if len(data.Ids) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Notification ids are required",
+			})
+		}
+		if len(data.Ids) > maxNotificationsBatch {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Too many notification ids",
+			})
+		}
+		query = query.Where("id IN ?", data.Ids)
+	}
+
+	if err := query.Update("is_read", true).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to mark notifications as read",
+		})
+	}
+
+	return c.JSON(MessageResponse{
+		Message: "Notifications marked as read",
+	})
+}
+
+// GetNotificationPreferences возвращает настройки уведомлений
+// @Summary      Настройки уведомлений
+// @Description  Возвращает для каждого типа уведомлений, показывать ли его в приложении (inApp) и отправлять ли на почту (email). По умолчанию на почту приходят только ответы на комментарии.
+// @Tags         Notification
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  DataResponse[[]NotificationPreferenceResponse]
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/getNotificationPreferences [get]
+func GetNotificationPreferences(c *fiber.Ctx, notifier *notify.Dispatcher) error {
+	user := CurrentUser(c)
+
+	return notificationPreferencesResponse(c, notifier, user.Id, "")
+}
+
+// UpdateNotificationPreferences изменяет настройки уведомлений
+// @Summary      Изменение настроек уведомлений
+// @Description  Сохраняет настройки доставки для перечисленных типов уведомлений, остальные типы не меняются. Письма отправляются только на подтвержденную почту.
+// @Tags         Notification
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      UpdateNotificationPreferencesRequest true "Настройки по типам уведомлений"
+// @Success      200   {object}  DataResponse[[]NotificationPreferenceResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/updateNotificationPreferences [patch]
+func UpdateNotificationPreferences(c *fiber.Ctx, notifier *notify.Dispatcher) error {
+	var data UpdateNotificationPreferencesRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	if len(data.Preferences) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Preferences are required",
+		})
+	}
+
+	// Повтор типа в одном запросе сломал бы upsert, поэтому действует последнее значение
+	byType := make(map[string]models.NotificationPreference, len(data.Preferences))
+	for _, p := range data.Preferences {
+		if _, ok := notify.DefaultPreferences[p.Type]; !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Invalid notification type: " + p.Type,
+			})
+		}
+		byType[p.Type] = models.NotificationPreference{
+			UserId: user.Id,
+			Type:   p.Type,
+			InApp:  p.InApp,
+			Email:  p.Email,
+		}
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(byType))
+	for _, p := range byType {
+		preferences = append(preferences, p)
+	}
+
+	if err := repository.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"in_app", "email"}),
+	}).Create(&preferences).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to update notification preferences",
+		})
+	}
+
+	return notificationPreferencesResponse(c, notifier, user.Id, "Notification preferences updated")
+}
+
+func notificationPreferencesResponse(c *fiber.Ctx, notifier *notify.Dispatcher, userId uint, message string) error {
+	preferences, err := notifier.Preferences(userId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch notification preferences",
+		})
+	}
+
+	response := make([]NotificationPreferenceResponse, 0, len(notify.Types))
+	for _, notificationType := range notify.Types {
+		response = append(response, NotificationPreferenceResponse{
+			Type:  notificationType,
+			InApp: preferences[notificationType].InApp,
+			Email: preferences[notificationType].Email,
+		})
+	}
+
+	return c.JSON(DataResponse[[]NotificationPreferenceResponse]{
+		Data:    response,
+		Message: message,
+	})
+}
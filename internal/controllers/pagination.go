@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/pagination"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parsePageParams разбирает query-параметры cursor и limit
+func parsePageParams(c *fiber.Ctx, kind pagination.Kind) (pagination.Params, error) {
+	return pagination.Parse(c.Query("cursor"), c.Query("limit"), kind)
+}
+
+// invalidPageParams отвечает 400 на невалидный курсор или размер страницы
+func invalidPageParams(c *fiber.Ctx, err error) error {
+	message := "Invalid cursor"
+	if errors.Is(err, pagination.ErrInvalidLimit) {
+		message = "Invalid limit value"
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+		Message: message,
+	})
+}
+
+// newPageResponse упаковывает страницу в ответ. Курсор last указывает на последний элемент страницы
+// и передается клиенту, только если есть следующая страница.
+func newPageResponse[T any](data T, hasMore bool, last pagination.Cursor) PageResponse[T] {
+	response := PageResponse[T]{
+		Data:    data,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		response.NextCursor = pagination.Encode(last)
+	}
+	return response
+}
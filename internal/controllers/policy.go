@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"log"
+)
+
+// Действия, записываемые в журнал модерации
+const (
+	actionEditPost        = "edit_post"
+	actionDeletePost      = "delete_post"
+	actionHidePost        = "hide_post"
+	actionUnhidePost      = "unhide_post"
+	actionDeleteComment   = "delete_comment"
+	actionHideComment     = "hide_comment"
+	actionAddModerator    = "add_moderator"
+	actionRemoveModerator = "remove_moderator"
+)
+
+// RequireRole пропускает дальше только пользователей с одной из указанных ролей.
+// Подключается после RequireAuth.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !CurrentUser(c).HasRole(roles...) {
+			c.Status(fiber.StatusForbidden)
+			return c.JSON(ErrorResponse{
+				Message: "Insufficient permissions",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// isStaff сообщает, что пользователь — модератор или администратор платформы
+func isStaff(user *models.User) bool {
+	return user.HasRole(models.RoleModerator, models.RoleAdmin)
+}
+
+// canManageChannel разрешает изменение и удаление канала владельцу и персоналу платформы
+func canManageChannel(user *models.User, channel *models.Channel) bool {
+	return channel.OwnerId == user.Id || isStaff(user)
+}
+
+// isChannelModerator сообщает, назначен ли пользователь модератором канала
+func isChannelModerator(userId uint, channelId uint) bool {
+	var count int64
+	repository.DB.Model(&models.ChannelModerator{}).
+		Where("channel_id = ? AND moderator_id = ?", channelId, userId).
+		Count(&count)
+	return count > 0
+}
+
+// canModerateChannel разрешает модерацию содержимого канала владельцу, модераторам канала и персоналу платформы
+func canModerateChannel(user *models.User, channel *models.Channel) bool {
+	return canManageChannel(user, channel) || isChannelModerator(user.Id, channel.Id)
+}
+
+// canManagePost разрешает редактирование, скрытие и удаление поста тем, кто может модерировать его канал
+func canManagePost(user *models.User, channel *models.Channel) bool {
+	return canModerateChannel(user, channel)
+}
+
+// canDeleteComment разрешает удаление комментария автору и тем, кто может модерировать канал поста
+func canDeleteComment(user *models.User, comment *models.Comment, channel *models.Channel) bool {
+	return comment.UserId == user.Id || canModerateChannel(user, channel)
+}
+
+// canViewHiddenPost разрешает просмотр скрытых и неопубликованных постов канала тем, кто может его модерировать.
+// Анонимный пользователь скрытые посты не видит.
+func canViewHiddenPost(user *models.User, channelId uint) bool {
+	if user == nil {
+		return false
+	}
+
+	var channel models.Channel
+	if err := repository.DB.First(&channel, channelId).Error; err != nil {
+		return false
+	}
+
+	return canModerateChannel(user, &channel)
+}
+
+// canViewPost разрешает просмотр опубликованного нескрытого поста всем, остальных — тем, кто может модерировать канал
+func canViewPost(user *models.User, post *models.Post) bool {
+	if !post.IsHidden && post.Status == models.PostStatusPublished {
+		return true
+	}
+	return canViewHiddenPost(user, post.ChannelId)
+}
+
+// logModeration записывает действие модерации в журнал канала
+func logModeration(actor *models.User, channelId uint, action string, targetType string, targetId uint, details string) {
+	entry := models.ModerationLog{
+		ActorId:    &actor.Id,
+		ChannelId:  &channelId,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Details:    details,
+	}
+
+	if err := repository.DB.Create(&entry).Error; err != nil {
+		log.Printf("Не удалось записать действие модерации %s: %v", action, err)
+	}
+
+	log.Printf("Модерация: пользователь %d, канал %d, %s %s %d %s", actor.Id, channelId, action, targetType, targetId, details)
+}
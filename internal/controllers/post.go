@@ -2,26 +2,79 @@ package controllers
 
 import (
 	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/notify"
+	"blogpoint-backend/internal/pagination"
+	"blogpoint-backend/internal/realtime"
+	"blogpoint-backend/internal/render"
 	"blogpoint-backend/internal/repository"
-	"blogpoint-backend/internal/storage"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
+var (
+	errInvalidPostStatus = errors.New("invalid post status")
+	errInvalidPublishAt  = errors.New("publish time must be in the future")
+)
+
+// postStatusTransitions — допустимые переходы между статусами поста. Пустой статус — новый пост.
+var postStatusTransitions = map[string][]string{
+	"":                         {models.PostStatusDraft, models.PostStatusScheduled, models.PostStatusPublished},
+	models.PostStatusDraft:     {models.PostStatusScheduled, models.PostStatusPublished},
+	models.PostStatusScheduled: {models.PostStatusDraft, models.PostStatusScheduled, models.PostStatusPublished},
+	models.PostStatusPublished: {models.PostStatusArchived},
+	models.PostStatusArchived:  {models.PostStatusPublished},
+}
+
+// applyPostStatus переводит пост в новый статус и выставляет время публикации.
+// При повторной публикации архивного поста сохраняется исходное время публикации.
+func applyPostStatus(post *models.Post, status string, publishAt *time.Time) error {
+	if !slices.Contains(postStatusTransitions[""], status) && status != models.PostStatusArchived {
+		return errInvalidPostStatus
+	}
+
+	switch status {
+	case models.PostStatusDraft:
+		post.PublishAt = nil
+	case models.PostStatusScheduled:
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return errInvalidPublishAt
+		}
+		post.PublishAt = publishAt
+	case models.PostStatusPublished:
+		post.PublishAt = nil
+		if post.PublishedAt == nil {
+			now := time.Now()
+			post.PublishedAt = &now
+		}
+	}
+
+	post.Status = status
+	return nil
+}
+
+// postStatusErrorMessage возвращает текст ответа для ошибки applyPostStatus
+func postStatusErrorMessage(err error) string {
+	if errors.Is(err, errInvalidPublishAt) {
+		return "Publish time must be in the future"
+	}
+	return "Invalid post status"
+}
+
 // CreatePost создает новый пост
 // @Summary      Создание поста
-// @Description  Создает пост в указанном канале. Пользователь должен быть владельцем канала.
+// @Description  Создает пост в указанном канале. Пользователь должен быть владельцем канала. Текст может быть в формате plain (по умолчанию) или markdown; изображения, прикрепленные к посту, вставляются в markdown ссылкой вида ![подпись](image:Id). По умолчанию пост публикуется сразу; со статусом draft сохраняется черновик, со статусом scheduled и временем publishAt пост будет опубликован автоматически.
 // @Tags         Post
 // @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
-// @Param        data  body      CreatePostRequest true "Данные поста (channelId, title, content, tags)"
+// @Param        data  body      CreatePostRequest true "Данные поста (channelId, title, content, tags, status?, publishAt?)"
 // @Success      200   {object}  DataResponse[PostResponse]
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
@@ -29,39 +82,14 @@ import (
 // @Failure      404   {object}  ErrorResponse
 // @Failure      409   {object}  ErrorResponse
 // @Router       /api/createPost [post]
-func CreatePost(c *fiber.Ctx) error {
+func CreatePost(c *fiber.Ctx, notifier *notify.Dispatcher) error {
 	var data CreatePostRequest
 
 	if err := json.Unmarshal(c.Body(), &data); err != nil {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	if data.ChannelId == 0 || data.Title == "" || data.Content == "" {
 		c.Status(fiber.StatusBadRequest)
@@ -78,7 +106,7 @@ func CreatePost(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(userId) {
+	if channel.OwnerId != user.Id {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this channel",
@@ -97,6 +125,19 @@ func CreatePost(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "No tag Ids provided"})
 	}
 
+	if data.Status == "" {
+		data.Status = models.PostStatusPublished
+	}
+
+	if data.ContentFormat == "" {
+		data.ContentFormat = render.FormatPlain
+	}
+	if !render.IsValidFormat(data.ContentFormat) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid content format",
+		})
+	}
+
 	var previewFile *models.File
 	if data.PreviewImageId != nil {
 		if err := repository.DB.First(&previewFile, *data.PreviewImageId).Error; err != nil {
@@ -106,7 +147,7 @@ func CreatePost(c *fiber.Ctx) error {
 			})
 		}
 
-		if previewFile.OwnerId != uint(userId) {
+		if previewFile.OwnerId != user.Id {
 			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Message: "You don't own the preview image"})
 		}
 
@@ -123,13 +164,26 @@ func CreatePost(c *fiber.Ctx) error {
 		PreviewImageId: data.PreviewImageId,
 		Title:          data.Title,
 		Content:        data.Content,
+		ContentFormat:  data.ContentFormat,
+	}
+
+	if err := applyPostStatus(&post, data.Status, data.PublishAt); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: postStatusErrorMessage(err),
+		})
 	}
 
 	var tags []models.Tag
 	var postImages []models.File
 	var postFiles []models.File
 
-	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		postSlug, err := repository.UniquePostSlug(tx, post.Title, post.ChannelId, 0)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate post slug")
+		}
+		post.Slug = postSlug
+
 		if err := tx.Create(&post).Error; err != nil {
 			return fiber.NewError(fiber.StatusInternalServerError, "Failed to create post")
 		}
@@ -148,7 +202,7 @@ func CreatePost(c *fiber.Ctx) error {
 			}
 
 			for _, file := range postImages {
-				if file.OwnerId != uint(userId) {
+				if file.OwnerId != user.Id {
 					return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("You don't own file with Id %d", file.Id))
 				}
 				if strings.Split(file.MimeType, "/")[0] != "image" {
@@ -166,11 +220,22 @@ func CreatePost(c *fiber.Ctx) error {
 			if err := tx.Where("id IN ?", data.PostFiles).Find(&postFiles).Error; err != nil || len(postFiles) != len(data.PostFiles) {
 				return fiber.NewError(fiber.StatusBadRequest, "One or more post file Ids are invalid")
 			}
+
+			for _, file := range postFiles {
+				if file.OwnerId != user.Id {
+					return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("You don't own file with Id %d", file.Id))
+				}
+			}
+
 			if err := tx.Model(&post).Association("PostFiles").Append(postFiles); err != nil {
 				return fiber.NewError(fiber.StatusInternalServerError, "Failed to attach post files")
 			}
 		}
 
+		if err := savePostRevision(tx, post.Id, &user.Id); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to save post revision")
+		}
+
 		return nil
 	})
 
@@ -184,6 +249,10 @@ func CreatePost(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Failed to load post with tags"})
 	}
 
+	if post.Status == models.PostStatusPublished {
+		notifier.PostPublished(post, channel)
+	}
+
 	return c.JSON(DataResponse[PostResponse]{
 		Data:    ConvertPostToResponse(post, previewFile),
 		Message: "Post created successfully",
@@ -192,7 +261,7 @@ func CreatePost(c *fiber.Ctx) error {
 
 // EditPost редактирует пост
 // @Summary      Редактирование поста
-// @Description  Изменяет заголовок, содержимое и теги поста. Пользователь должен быть владельцем канала.
+// @Description  Изменяет заголовок, содержимое и теги поста. Каждая правка сохраняется в истории изменений. Доступно владельцу канала, модераторам и администраторам.
 // @Tags         Post
 // @Security     ApiKeyAuth
 // @Accept       json
@@ -211,32 +280,7 @@ func EditPost(c *fiber.Ctx) error {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	uintId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	if data.PostId == 0 {
 		c.Status(fiber.StatusBadRequest)
@@ -261,19 +305,28 @@ func EditPost(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(uintId) {
+	if !canManagePost(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this post",
 		})
 	}
 
+	titleChanged := data.Title != "" && data.Title != post.Title
 	if data.Title != "" {
 		post.Title = data.Title
 	}
 	if data.Content != "" {
 		post.Content = data.Content
 	}
+	if data.ContentFormat != "" {
+		if !render.IsValidFormat(data.ContentFormat) {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Message: "Invalid content format",
+			})
+		}
+		post.ContentFormat = data.ContentFormat
+	}
 
 	var previewFile *models.File
 	if data.PreviewImageId != nil {
@@ -281,7 +334,7 @@ func EditPost(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusBadRequest, "Preview image does not exist")
 		}
 
-		if previewFile.OwnerId != uint(uintId) {
+		if previewFile.OwnerId != user.Id {
 			return fiber.NewError(fiber.StatusForbidden, "You don't own the preview image")
 		}
 
@@ -301,7 +354,17 @@ func EditPost(c *fiber.Ctx) error {
 	}
 
 	// Начинаем транзакцию
-	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, post.Id); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to save post revision")
+		}
+
+		if titleChanged {
+			if err := updatePostSlug(tx, &post); err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, "Failed to update post slug")
+			}
+		}
+
 		if err := tx.Save(&post).Error; err != nil {
 			return fiber.NewError(fiber.StatusInternalServerError, "Failed to update post")
 		}
@@ -323,7 +386,7 @@ func EditPost(c *fiber.Ctx) error {
 				return fiber.NewError(fiber.StatusBadRequest, "One or more post image IDs are invalid")
 			}
 			for _, file := range postImages {
-				if file.OwnerId != uint(uintId) {
+				if file.OwnerId != user.Id {
 					return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("You don't own image with ID %d", file.Id))
 				}
 				if strings.Split(file.MimeType, "/")[0] != "image" {
@@ -341,7 +404,7 @@ func EditPost(c *fiber.Ctx) error {
 				return fiber.NewError(fiber.StatusBadRequest, "One or more post file IDs are invalid")
 			}
 			for _, file := range postFiles {
-				if file.OwnerId != uint(uintId) {
+				if file.OwnerId != user.Id {
 					return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("You don't own file with ID %d", file.Id))
 				}
 			}
@@ -350,6 +413,10 @@ func EditPost(c *fiber.Ctx) error {
 			}
 		}
 
+		if err := savePostRevision(tx, post.Id, &user.Id); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to save post revision")
+		}
+
 		return nil
 	})
 
@@ -359,6 +426,10 @@ func EditPost(c *fiber.Ctx) error {
 		})
 	}
 
+	if user.Id != channel.OwnerId {
+		logModeration(user, channel.Id, actionEditPost, "post", post.Id, post.Title)
+	}
+
 	if err := repository.DB.Preload("Tags").First(&post, data.PostId).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Failed to load post with tags"})
 	}
@@ -371,7 +442,7 @@ func EditPost(c *fiber.Ctx) error {
 
 // DeletePost удаляет пост
 // @Summary      Удаление поста
-// @Description  Удаляет пост, если пользователь — владелец канала
+// @Description  Удаляет пост, если пользователь — владелец канала, модератор или администратор
 // @Tags         Post
 // @Security     ApiKeyAuth
 // @Accept       json
@@ -384,32 +455,7 @@ func EditPost(c *fiber.Ctx) error {
 // @Failure      404   {object}  ErrorResponse
 // @Router       /api/deletePost/{id} [delete]
 func DeletePost(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	Id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || Id == 0 {
@@ -435,7 +481,7 @@ func DeletePost(c *fiber.Ctx) error {
 		})
 	}
 
-	if channel.OwnerId != uint(userId) {
+	if !canManagePost(user, &channel) {
 		c.Status(fiber.StatusForbidden)
 		return c.JSON(ErrorResponse{
 			Message: "You are not the owner of this post",
@@ -449,14 +495,169 @@ func DeletePost(c *fiber.Ctx) error {
 		})
 	}
 
+	if user.Id != channel.OwnerId {
+		logModeration(user, channel.Id, actionDeletePost, "post", post.Id, post.Title)
+	}
+
 	return c.JSON(MessageResponse{
 		Message: "Post successfully deleted",
 	})
 }
 
+// SetPostHidden скрывает пост из публичных списков или возвращает его обратно
+// @Summary      Скрытие поста
+// @Description  Снимает пост с публикации или возвращает его. Доступно владельцу канала, модераторам канала, модераторам и администраторам платформы.
+// @Tags         Post
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      SetPostHiddenRequest true "Id поста и признак скрытия"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/setPostHidden [patch]
+func SetPostHidden(c *fiber.Ctx) error {
+	var data SetPostHiddenRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	var post models.Post
+	if err := repository.DB.First(&post, data.PostId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Post not found",
+		})
+	}
+
+	var channel models.Channel
+	if err := repository.DB.First(&channel, post.ChannelId).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	if !canManagePost(user, &channel) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not allowed to moderate this post",
+		})
+	}
+
+	if err := repository.DB.Model(&post).Update("is_hidden", data.Hidden).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to update post",
+		})
+	}
+
+	if user.Id != channel.OwnerId {
+		action := actionUnhidePost
+		if data.Hidden {
+			action = actionHidePost
+		}
+		logModeration(user, channel.Id, action, "post", post.Id, post.Title)
+	}
+
+	return c.JSON(MessageResponse{
+		Message: "Post visibility updated",
+	})
+}
+
+// SetPostStatus меняет статус публикации поста
+// @Summary      Изменение статуса поста
+// @Description  Переводит пост между статусами draft, scheduled, published и archived. Для статуса scheduled нужно время publishAt в будущем. Опубликованный пост можно только архивировать, архивный — опубликовать снова. Доступно тем, кто может управлять постами канала.
+// @Tags         Post
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      SetPostStatusRequest true "Id поста, новый статус и время публикации"
+// @Success      200   {object}  DataResponse[PostResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/setPostStatus [patch]
+func SetPostStatus(c *fiber.Ctx, notifier *notify.Dispatcher) error {
+	var data SetPostStatusRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	var post models.Post
+	if err := repository.DB.Preload("Tags").Preload("PostImages").Preload("PostFiles").
+		First(&post, data.PostId).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Post not found",
+		})
+	}
+
+	var channel models.Channel
+	if err := repository.DB.First(&channel, post.ChannelId).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	if !canManagePost(user, &channel) {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not allowed to manage this post",
+		})
+	}
+
+	if !slices.Contains(postStatusTransitions[post.Status], data.Status) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: fmt.Sprintf("Cannot change post status from %s to %s", post.Status, data.Status),
+		})
+	}
+
+	firstPublication := post.PublishedAt == nil
+
+	if err := applyPostStatus(&post, data.Status, data.PublishAt); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: postStatusErrorMessage(err),
+		})
+	}
+
+	if err := repository.DB.Model(&post).Updates(map[string]interface{}{
+		"status":       post.Status,
+		"publish_at":   post.PublishAt,
+		"published_at": post.PublishedAt,
+	}).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to update post status",
+		})
+	}
+
+	// О повторной публикации архивного поста подписчиков не уведомляем
+	if firstPublication && post.Status == models.PostStatusPublished {
+		notifier.PostPublished(post, channel)
+	}
+
+	var previewFile *models.File
+	if post.PreviewImageId != nil {
+		var file models.File
+		if err := repository.DB.First(&file, *post.PreviewImageId).Error; err == nil {
+			previewFile = &file
+		}
+	}
+
+	return c.JSON(DataResponse[PostResponse]{
+		Data:    ConvertPostToResponse(post, previewFile),
+		Message: "Post status updated",
+	})
+}
+
 // GetPost возвращает пост по Id
 // @Summary      Получение поста
-// @Description  Возвращает пост с тегами по Id
+// @Description  Возвращает пост с тегами по Id. Неопубликованные посты видны только тем, кто может управлять каналом.
 // @Tags         Post
 // @Accept       json
 // @Produce      json
@@ -487,6 +688,59 @@ func GetPost(c *fiber.Ctx) error {
 		})
 	}
 
+	return postDetailsResponse(c, post)
+}
+
+// GetPostBySlug возвращает пост по слагам канала и поста
+// @Summary      Получение поста по слагу
+// @Description  Возвращает пост по слагу канала и слагу поста. Если канал или пост переименованы, со старого слага отвечает 301 с адресом по текущим слагам.
+// @Tags         Post
+// @Produce      json
+// @Param        channelSlug  path      string true "Слаг канала"
+// @Param        postSlug     path      string true "Слаг поста"
+// @Success      200          {object}  DataResponse[PostResponse]
+// @Success      301          {string}  string "Перенаправление на текущий адрес поста"
+// @Failure      404          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /api/getPostBySlug/{channelSlug}/{postSlug} [get]
+func GetPostBySlug(c *fiber.Ctx) error {
+	channel, redirected, err := findChannelBySlug(c.Params("channelSlug"))
+	if err != nil {
+		return slugLookupError(c, err, "Channel")
+	}
+
+	postSlug := c.Params("postSlug")
+	query := repository.DB.Preload("Tags").Preload("PostImages").Preload("PostFiles")
+
+	var post models.Post
+	err = query.Where("channel_id = ? AND slug = ?", channel.Id, postSlug).First(&post).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var redirect models.PostSlugRedirect
+		if err = repository.DB.Where("channel_id = ? AND slug = ?", channel.Id, postSlug).First(&redirect).Error; err == nil {
+			err = query.First(&post, redirect.PostId).Error
+			redirected = true
+		}
+	}
+	if err != nil {
+		return slugLookupError(c, err, "Post")
+	}
+
+	// Проверка до перенаправления, чтобы старый слаг не раскрывал скрытый пост
+	if redirected && canViewPost(CurrentUser(c), &post) {
+		return c.Redirect("/api/getPostBySlug/"+channel.Slug+"/"+post.Slug, fiber.StatusMovedPermanently)
+	}
+
+	return postDetailsResponse(c, post)
+}
+
+// postDetailsResponse проверяет доступ к посту, учитывает просмотр и отдает пост целиком
+func postDetailsResponse(c *fiber.Ctx, post models.Post) error {
+	if !canViewPost(CurrentUser(c), &post) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Post not found",
+		})
+	}
+
 	if err := repository.DB.Model(&post).UpdateColumn("views_count", gorm.Expr("views_count + 1")).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Message: "Failed to update view count",
@@ -508,14 +762,17 @@ func GetPost(c *fiber.Ctx) error {
 
 // GetPosts получает посты по Id канала с пагинацией
 // @Summary      Get posts
-// @Description  Получает список постов по Id канала с пагинацией (по 10 на страницу)
+// @Description  Получает список постов по Id канала, новые сначала, с курсорной пагинацией
 // @Tags         Post
 // @Accept       json
 // @Produce      json
-// @Param        channelId  path      int true  "Id канала"
-// @Param        page       query     int false "Номер страницы (по умолчанию 1)"
-// @Success      200        {array}   DataResponse[[]PostResponse]
+// @Param        channelId  path      int    true  "Id канала"
+// @Param        status     query     string false "Статус: published (по умолчанию), draft, scheduled, archived. Неопубликованные посты доступны тем, кто может управлять каналом"
+// @Param        cursor     query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit      query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200        {object}  PageResponse[[]PostResponse]
 // @Failure      400        {object}  ErrorResponse
+// @Failure      403        {object}  ErrorResponse
 // @Failure      500        {object}  ErrorResponse
 // @Router       /api/getPosts/{channelId} [get]
 func GetPosts(c *fiber.Ctx) error {
@@ -527,24 +784,50 @@ func GetPosts(c *fiber.Ctx) error {
 		})
 	}
 
-	page, err := strconv.Atoi(c.Query("page", "1"))
-	if err != nil || page <= 0 {
+	params, err := parsePageParams(c, pagination.ByTime)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	status := c.Query("status", models.PostStatusPublished)
+	if _, ok := postStatusTransitions[status]; !ok || status == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Message: "Invalid page value",
+			Message: "Invalid post status",
 		})
 	}
 
-	offset := (page - 1) * 10
+	canViewAll := canViewHiddenPost(CurrentUser(c), uint(channelId))
+	if status != models.PostStatusPublished && !canViewAll {
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Message: "You are not allowed to view unpublished posts of this channel",
+		})
+	}
+
+	// Опубликованные посты идут по времени публикации, остальные — по времени создания
+	sortColumn := "created_at"
+	if status == models.PostStatusPublished {
+		sortColumn = "published_at"
+	}
+
+	query := repository.DB.Preload("Tags").Preload("PostImages").Preload("PostFiles").
+		Where("channel_id = ? AND status = ?", channelId, status)
+	if !canViewAll {
+		query = query.Where("is_hidden = FALSE")
+	}
+	if params.Cursor != nil {
+		query = query.Where("("+sortColumn+", id) < (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
 
 	var posts []models.Post
-	if err := repository.DB.Preload("Tags").Preload("PostImages").Preload("PostFiles").
-		Where("channel_id = ?", channelId).Limit(10).Offset(offset).Find(&posts).Error; err != nil {
+	if err := query.Order(sortColumn + " DESC, id DESC").Limit(params.Limit + 1).Find(&posts).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Message: "Failed to fetch posts",
 		})
 	}
 
-	var postsResponse []PostResponse
+	posts, hasMore := pagination.Trim(posts, params.Limit)
+
+	postsResponse := make([]PostResponse, 0, len(posts))
 	for _, post := range posts {
 
 		var preview *models.File
@@ -567,65 +850,62 @@ func GetPosts(c *fiber.Ctx) error {
 		postsResponse = append(postsResponse, ConvertPostToResponse(post, preview))
 	}
 
-	return c.JSON(DataResponse[[]PostResponse]{
-		Data: postsResponse,
-	})
+	var last pagination.Cursor
+	if len(posts) > 0 {
+		lastPost := posts[len(posts)-1]
+		sortValue := lastPost.CreatedAt
+		if lastPost.PublishedAt != nil && status == models.PostStatusPublished {
+			sortValue = *lastPost.PublishedAt
+		}
+		last = pagination.TimeCursor(sortValue, lastPost.Id)
+	}
+
+	return c.JSON(newPageResponse(postsResponse, hasMore, last))
 }
 
-// GetRecommendedPosts возвращает список рекомендуемых постов по кастомной формуле
-// @Summary      Рекомендуемые посты
-// @Description  Получает список рекомендуемых постов за последнюю неделю, сортируя по формуле: views + likes*3 - dislikes*2 + comments*2
-// @Tags         Post
-// @Accept       json
-// @Produce      json
-// @Param        page  query     int false "Номер страницы (по умолчанию 1)"
-// @Success      200   {object}  DataResponse[[]PostResponse]
-// @Failure      400   {object}  ErrorResponse
-// @Failure      500   {object}  ErrorResponse
-// @Router       /api/getRecommendedPosts [get]
-func GetRecommendedPosts(c *fiber.Ctx) error {
-	page, err := strconv.Atoi(c.Query("page", "1"))
-	if err != nil || page <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Message: "Invalid page value",
-		})
+// updatePostSlug подбирает слаг под новый заголовок поста и сохраняет перенаправление со старого слага
+func updatePostSlug(tx *gorm.DB, post *models.Post) error {
+	newSlug, err := repository.UniquePostSlug(tx, post.Title, post.ChannelId, post.Id)
+	if err != nil || newSlug == post.Slug {
+		return err
 	}
 
-	offset := (page - 1) * 10
+	if err = repository.SavePostSlugRedirect(tx, post.Slug, post.ChannelId, post.Id); err != nil {
+		return err
+	}
 
-	type PostWithRating struct {
-		Id            uint
-		CommentsCount int `json:"comments_count"`
-		Rating        int `json:"-"`
+	// Пост мог вернуться к одному из своих прежних слагов
+	if err = tx.Where("channel_id = ? AND slug = ? AND post_id = ?", post.ChannelId, newSlug, post.Id).
+		Delete(&models.PostSlugRedirect{}).Error; err != nil {
+		return err
 	}
 
-	var postInfos []PostWithRating
+	post.Slug = newSlug
+	return nil
+}
+
+// postRatingExpr — рейтинг поста: views + likes*3 - dislikes*2 + comments*2.
+// Ожидает подзапрос c с количеством комментариев, см. ratedPostsQuery.
+const postRatingExpr = "(posts.views_count + posts.likes_count * 3 - posts.dislikes_count * 2 + COALESCE(c.comments_count, 0) * 2)"
 
-	subQuery := repository.DB.
+// ratedPostsQuery выбирает посты с указанными колонками и вычисленным рейтингом rating
+func ratedPostsQuery(columns string, args ...interface{}) *gorm.DB {
+	commentsCount := repository.DB.
 		Table("comments").
 		Select("post_id, COUNT(*) as comments_count").
 		Group("post_id")
 
-	if err := repository.DB.
+	return repository.DB.
 		Table("posts").
-		Select(`posts.id, 
-		COALESCE(c.comments_count, 0) as comments_count,
-		(views_count + likes_count * 3 - dislikes_count * 2 + COALESCE(c.comments_count, 0) * 2) as rating`).
-		Joins("LEFT JOIN (?) as c ON posts.id = c.post_id", subQuery).
-		Where("posts.created_at >= NOW() - INTERVAL '7 days'").
-		Order("rating DESC").
-		Limit(10).Offset(offset).
-		Scan(&postInfos).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
-			Message: "Failed to fetch popular post IDs",
-		})
-	}
+		Select(columns+", "+postRatingExpr+" as rating", args...).
+		Joins("LEFT JOIN (?) as c ON posts.id = c.post_id", commentsCount)
+}
 
-	ids := make([]uint, len(postInfos))
-	indexMap := make(map[uint]int, len(postInfos))
-	for i, p := range postInfos {
-		ids[i] = p.Id
-		indexMap[p.Id] = i
+// postResponsesByIds загружает посты по списку id и возвращает их в том же порядке
+func postResponsesByIds(ids []uint) ([]PostResponse, error) {
+	indexMap := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		indexMap[id] = i
 	}
 
 	var posts []models.Post
@@ -635,20 +915,23 @@ func GetRecommendedPosts(c *fiber.Ctx) error {
 		Preload("PostFiles").
 		Where("id IN ?", ids).
 		Find(&posts).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
-			Message: "Failed to fetch posts",
-		})
+		return nil, err
 	}
 
-	sortedPosts := make([]models.Post, len(posts))
+	// Пост мог быть удален между запросами, такие места остаются пустыми и пропускаются
+	sortedPosts := make([]models.Post, len(ids))
 	for _, post := range posts {
 		if idx, ok := indexMap[post.Id]; ok {
 			sortedPosts[idx] = post
 		}
 	}
 
-	postsResponse := make([]PostResponse, 0, len(sortedPosts))
+	postsResponse := make([]PostResponse, 0, len(posts))
 	for _, post := range sortedPosts {
+		if post.Id == 0 {
+			continue
+		}
+
 		var preview *models.File
 		if post.PreviewImageId != nil {
 			var file models.File
@@ -659,9 +942,76 @@ func GetRecommendedPosts(c *fiber.Ctx) error {
 		postsResponse = append(postsResponse, ConvertPostToResponse(post, preview))
 	}
 
-	return c.JSON(DataResponse[[]PostResponse]{
-		Data: postsResponse,
-	})
+	return postsResponse, nil
+}
+
+// GetRecommendedPosts возвращает список рекомендуемых постов по кастомной формуле
+// @Summary      Рекомендуемые посты
+// @Description  Получает список рекомендуемых постов за последнюю неделю, сортируя по формуле: views + likes*3 - dislikes*2 + comments*2. Рейтинг пересчитывается на каждый запрос, поэтому страницы приблизительные: пост может повториться или выпасть при переходе по курсору
+// @Tags         Post
+// @Accept       json
+// @Produce      json
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]PostResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/getRecommendedPosts [get]
+func GetRecommendedPosts(c *fiber.Ctx) error {
+	params, err := parsePageParams(c, pagination.ByScore)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	type PostWithRating struct {
+		Id            uint
+		CommentsCount int `json:"comments_count"`
+		Rating        int `json:"-"`
+	}
+
+	var postInfos []PostWithRating
+
+	ratedPosts := ratedPostsQuery("posts.id, COALESCE(c.comments_count, 0) as comments_count").
+		Where("posts.status = ? AND posts.published_at >= NOW() - INTERVAL '7 days' AND posts.is_hidden = FALSE",
+			models.PostStatusPublished)
+
+	// Рейтинг вычисляемый, поэтому курсор сравнивается с ним во внешнем запросе. Между запросами рейтинг
+	// меняется, и курсор задает только порог: пост может повториться или выпасть на следующих страницах
+	query := repository.DB.Table("(?) as rated", ratedPosts)
+	if params.Cursor != nil {
+		query = query.Where("(rating, id) < (?, ?)", int64(*params.Cursor.Score), params.Cursor.Id)
+	}
+
+	if err := query.
+		Order("rating DESC, id DESC").
+		Limit(params.Limit + 1).
+		Scan(&postInfos).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch popular post IDs",
+		})
+	}
+
+	postInfos, hasMore := pagination.Trim(postInfos, params.Limit)
+
+	ids := make([]uint, len(postInfos))
+	for i, p := range postInfos {
+		ids[i] = p.Id
+	}
+
+	postsResponse, err := postResponsesByIds(ids)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch posts",
+		})
+	}
+
+	var last pagination.Cursor
+	if len(postInfos) > 0 {
+		lastInfo := postInfos[len(postInfos)-1]
+		last = pagination.ScoreCursor(float64(lastInfo.Rating), lastInfo.Id)
+	}
+
+	return c.JSON(newPageResponse(postsResponse, hasMore, last))
 }
 
 // SetReaction устанавливает реакцию пользователя на пост
@@ -677,39 +1027,14 @@ func GetRecommendedPosts(c *fiber.Ctx) error {
 // @Failure      401   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /api/setReaction [post]
-func SetReaction(c *fiber.Ctx) error {
+func SetReaction(c *fiber.Ctx, hub *realtime.Hub) error {
 	var data SetReactionRequest
 
 	if err := json.Unmarshal(c.Body(), &data); err != nil {
 		return err
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	if data.PostId == 0 {
 		c.Status(fiber.StatusBadRequest)
@@ -718,6 +1043,15 @@ func SetReaction(c *fiber.Ctx) error {
 		})
 	}
 
+	var post models.Post
+	// Реагировать можно только на опубликованные посты, которые не скрыты модерацией
+	if err := repository.DB.Select("id", "status", "is_hidden").First(&post, data.PostId).Error; err != nil ||
+		post.Status != models.PostStatusPublished || post.IsHidden {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Post not found",
+		})
+	}
+
 	if data.Reaction != "like" && data.Reaction != "dislike" {
 		c.Status(fiber.StatusBadRequest)
 		return c.JSON(ErrorResponse{
@@ -728,7 +1062,7 @@ func SetReaction(c *fiber.Ctx) error {
 	reactionValue := data.Reaction == "like"
 
 	var existingReaction models.PostReaction
-	if err := repository.DB.Where("post_id = ? AND user_id = ?", data.PostId, userId).
+	if err := repository.DB.Where("post_id = ? AND user_id = ?", data.PostId, user.Id).
 		First(&existingReaction).Error; err == nil {
 		if existingReaction.Reaction == reactionValue {
 			if err := repository.DB.Delete(&existingReaction).Error; err != nil {
@@ -737,6 +1071,7 @@ func SetReaction(c *fiber.Ctx) error {
 					"message": "Failed to remove reaction",
 				})
 			}
+			publishReactionCounts(hub, post.Id)
 			return c.JSON(fiber.Map{
 				"message": "Reaction removed",
 			})
@@ -749,6 +1084,7 @@ func SetReaction(c *fiber.Ctx) error {
 				"message": "Failed to update reaction",
 			})
 		}
+		publishReactionCounts(hub, post.Id)
 		return c.JSON(ErrorResponse{
 			Message: "Reaction updated",
 		})
@@ -756,7 +1092,7 @@ func SetReaction(c *fiber.Ctx) error {
 
 	newReaction := models.PostReaction{
 		PostId:   data.PostId,
-		UserId:   uint(userId),
+		UserId:   user.Id,
 		Reaction: reactionValue,
 	}
 
@@ -767,11 +1103,26 @@ func SetReaction(c *fiber.Ctx) error {
 		})
 	}
 
+	publishReactionCounts(hub, post.Id)
 	return c.JSON(MessageResponse{
 		Message: "Reaction added",
 	})
 }
 
+// publishReactionCounts рассылает подписчикам поста счетчики реакций, пересчитанные триггером
+func publishReactionCounts(hub *realtime.Hub, postId uint) {
+	var post models.Post
+	if err := repository.DB.Select("id", "likes_count", "dislikes_count").First(&post, postId).Error; err != nil {
+		return
+	}
+
+	hub.Publish(realtime.PostTopic(postId), realtime.EventReactionUpdated, ReactionCountsResponse{
+		PostId:        post.Id,
+		LikesCount:    post.LikesCount,
+		DislikesCount: post.DislikesCount,
+	})
+}
+
 // CreateComment создает комментарий к посту или ответ на комментарий
 // @Summary      Создание комментария
 // @Description  Создает новый комментарий к посту или ответ на существующий комментарий
@@ -786,7 +1137,7 @@ func SetReaction(c *fiber.Ctx) error {
 // @Failure      404   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /api/createComment [post]
-func CreateComment(c *fiber.Ctx) error {
+func CreateComment(c *fiber.Ctx, notifier *notify.Dispatcher, hub *realtime.Hub) error {
 	var data CreateCommentRequest
 
 	if err := json.Unmarshal(c.Body(), &data); err != nil {
@@ -801,43 +1152,36 @@ func CreateComment(c *fiber.Ctx) error {
 		})
 	}
 
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
+	user := CurrentUser(c)
 
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
+	var post models.Post
+	if result := repository.DB.First(&post, data.PostId); result.Error != nil {
+		c.Status(fiber.StatusNotFound)
 		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
+			Message: "Post not found",
 		})
 	}
 
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
+	if !canViewPost(user, &post) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Post not found",
 		})
 	}
 
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
+	if post.Status != models.PostStatusPublished {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Post is not published",
 		})
 	}
 
-	var post models.Post
-	if result := repository.DB.First(&post, data.PostId); result.Error != nil {
-		c.Status(fiber.StatusNotFound)
-		return c.JSON(ErrorResponse{
-			Message: "Post not found",
+	if post.IsHidden {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Post is hidden",
 		})
 	}
 
+	var parent models.Comment
 	if data.ParentId != nil {
-		var parent models.Comment
 		if err := repository.DB.First(&parent, *data.ParentId).Error; err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
 				Message: "Parent comment not found",
@@ -852,7 +1196,7 @@ func CreateComment(c *fiber.Ctx) error {
 
 	comment := models.Comment{
 		PostId:   data.PostId,
-		UserId:   uint(userId),
+		UserId:   user.Id,
 		Content:  data.Content,
 		ParentId: data.ParentId,
 	}
@@ -863,6 +1207,24 @@ func CreateComment(c *fiber.Ctx) error {
 		})
 	}
 
+	hub.Publish(realtime.PostTopic(post.Id), realtime.EventCommentCreated, newCommentResponse(comment, *user))
+
+	if data.ParentId != nil && !parent.IsDeleted {
+		notifier.Notify(notify.Event{
+			Type:      notify.TypeCommentReply,
+			UserIds:   []uint{parent.UserId},
+			ActorId:   &user.Id,
+			ChannelId: &post.ChannelId,
+			PostId:    &post.Id,
+			CommentId: &comment.Id,
+			Data: models.NotificationData{
+				ActorLogin: user.Login,
+				PostTitle:  post.Title,
+				Text:       notify.Excerpt(comment.Content),
+			},
+		})
+	}
+
 	return c.JSON(DataResponse[models.Comment]{
 		Data:    comment,
 		Message: "Comment created successfully",
@@ -871,7 +1233,7 @@ func CreateComment(c *fiber.Ctx) error {
 
 // DeleteComment удаляет комментарий пользователя
 // @Summary      Удаление комментария
-// @Description  Удаляет комментарий, если нет ответов — полностью, иначе помечает как удаленный
+// @Description  Удаляет комментарий, если нет ответов — полностью, иначе помечает как удаленный. Доступно автору, модераторам и администраторам
 // @Tags         Comment
 // @Security     ApiKeyAuth
 // @Produce      json
@@ -884,32 +1246,7 @@ func CreateComment(c *fiber.Ctx) error {
 // @Failure      500  {object}  ErrorResponse
 // @Router       /api/deleteComment/{id} [delete]
 func DeleteComment(c *fiber.Ctx) error {
-	token, err := jwt.ParseWithClaims(c.Cookies("jwt"), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Unauthenticated",
-		})
-	}
-
-	strId, ok := token.Claims.(jwt.MapClaims)["iss"].(string)
-	if !ok {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid token",
-		})
-	}
-
-	userId, err := strconv.ParseUint(strId, 10, 32)
-	if err != nil {
-		c.Status(fiber.StatusUnauthorized)
-		return c.JSON(ErrorResponse{
-			Message: "Invalid issuer id",
-		})
-	}
+	user := CurrentUser(c)
 
 	Id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil || Id == 0 {
@@ -927,7 +1264,15 @@ func DeleteComment(c *fiber.Ctx) error {
 		})
 	}
 
-	if comment.UserId != uint(userId) {
+	var channel models.Channel
+	if err := repository.DB.Joins("JOIN posts ON posts.channel_id = channels.id").
+		Where("posts.id = ?", comment.PostId).First(&channel).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Channel not found",
+		})
+	}
+
+	if !canDeleteComment(user, &comment, &channel) {
 		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
 			Message: "You are not the author of this comment",
 		})
@@ -944,6 +1289,9 @@ func DeleteComment(c *fiber.Ctx) error {
 				Message: "Failed to mark comment as deleted",
 			})
 		}
+		if user.Id != comment.UserId {
+			logModeration(user, channel.Id, actionDeleteComment, "comment", comment.Id, fmt.Sprintf("Post %d", comment.PostId))
+		}
 		return c.JSON(MessageResponse{
 			Message: "Comment marked as deleted",
 		})
@@ -955,6 +1303,10 @@ func DeleteComment(c *fiber.Ctx) error {
 		})
 	}
 
+	if user.Id != comment.UserId {
+		logModeration(user, channel.Id, actionDeleteComment, "comment", comment.Id, fmt.Sprintf("Post %d", comment.PostId))
+	}
+
 	return c.JSON(MessageResponse{
 		Message: "Comment deleted successfully",
 	})
@@ -962,16 +1314,17 @@ func DeleteComment(c *fiber.Ctx) error {
 
 // GetPostComments возвращает корневые или дочерние комментарии поста с пагинацией
 // @Summary      Получение комментариев
-// @Description  Возвращает комментарии к посту, поддерживает пагинацию и фильтрацию по parentId
+// @Description  Возвращает комментарии к посту в порядке создания, поддерживает курсорную пагинацию и фильтрацию по parentId. Комментарии скрытого или неопубликованного поста доступны только тем, кто может видеть сам пост
 // @Tags         Comment
 // @Accept       json
 // @Produce      json
-// @Param        postId     query     int  true  "Id поста"
-// @Param        parentId   query     int  false "Id родительского комментария (для подкомментариев)"
-// @Param        offset     query     int  false "Смещение (для пагинации)"
-// @Param        limit      query     int  false "Количество комментариев на странице"
-// @Success      200  {object}  DataResponse[[]CommentResponse]
+// @Param        postId     query     int    true  "Id поста"
+// @Param        parentId   query     int    false "Id родительского комментария (для подкомментариев)"
+// @Param        cursor     query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit      query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200  {object}  PageResponse[[]CommentResponse]
 // @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /api/getPostComments [get]
 func GetPostComments(c *fiber.Ctx) error {
@@ -980,8 +1333,15 @@ func GetPostComments(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid postId"})
 	}
 
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	var post models.Post
+	if err = repository.DB.First(&post, postId).Error; err != nil || !canViewPost(CurrentUser(c), &post) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Message: "Post not found"})
+	}
+
+	params, err := parsePageParams(c, pagination.ByTime)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
 
 	var parentId *uint
 	if pid := c.Query("parentId"); pid != "" {
@@ -999,11 +1359,16 @@ func GetPostComments(c *fiber.Ctx) error {
 	} else {
 		query = query.Where("parent_id IS NULL")
 	}
-	query = query.Order("created_at ASC").Limit(limit).Offset(offset)
+	if params.Cursor != nil {
+		query = query.Where("(created_at, id) > (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
+	query = query.Order("created_at ASC, id ASC").Limit(params.Limit + 1)
 	if err := query.Find(&comments).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Failed to load comments"})
 	}
 
+	comments, hasMore := pagination.Trim(comments, params.Limit)
+
 	// Собираем userIds
 	userIds := make([]uint, 0)
 	for _, c := range comments {
@@ -1061,18 +1426,23 @@ func GetPostComments(c *fiber.Ctx) error {
 		var fileResponse *FileResponse
 		if user.LogoId != nil {
 			if file, ok := fileMap[*user.LogoId]; ok {
-				fileResponse = &FileResponse{
-					Id:  file.Id,
-					Url: storage.GetUrl(file.Filename),
-				}
+				response := newFileResponse(file)
+				fileResponse = &response
 			}
 		}
+		// Текст скрытого модератором комментария не отдаем, но оставляем место в ветке ответов
+		content := cmt.Content
+		if cmt.IsHidden {
+			content = ""
+		}
 		resp := CommentResponse{
 			Id:           cmt.Id,
 			PostId:       cmt.PostId,
 			ParentId:     cmt.ParentId,
-			Content:      cmt.Content,
+			Content:      content,
+			ContentHtml:  render.HTML(content, render.FormatPlain, nil),
 			IsDeleted:    cmt.IsDeleted,
+			IsHidden:     cmt.IsHidden,
 			RepliesCount: replyCountMap[cmt.Id],
 			User: struct {
 				Id    uint          `json:"id"`
@@ -1087,34 +1457,62 @@ func GetPostComments(c *fiber.Ctx) error {
 		commentResponses = append(commentResponses, resp)
 	}
 
-	return c.JSON(DataResponse[[]CommentResponse]{
-		Data: commentResponses,
-	})
+	var last pagination.Cursor
+	if len(comments) > 0 {
+		lastComment := comments[len(comments)-1]
+		last = pagination.TimeCursor(lastComment.CreatedAt, lastComment.Id)
+	}
+
+	return c.JSON(newPageResponse(commentResponses, hasMore, last))
+}
+
+// newCommentResponse собирает ответ для только что созданного комментария
+func newCommentResponse(comment models.Comment, user models.User) CommentResponse {
+	var logo *FileResponse
+	if user.LogoId != nil {
+		var file models.File
+		if err := repository.DB.First(&file, *user.LogoId).Error; err == nil {
+			response := newFileResponse(file)
+			logo = &response
+		}
+	}
+
+	return CommentResponse{
+		Id:          comment.Id,
+		PostId:      comment.PostId,
+		ParentId:    comment.ParentId,
+		Content:     comment.Content,
+		ContentHtml: render.HTML(comment.Content, render.FormatPlain, nil),
+		User: struct {
+			Id    uint          `json:"id"`
+			Login string        `json:"login"`
+			Logo  *FileResponse `json:"logo"`
+		}{
+			Id:    user.Id,
+			Login: user.Login,
+			Logo:  logo,
+		},
+	}
 }
 
 func ConvertPostToResponse(post models.Post, previewImage *models.File) PostResponse {
 	var preview *FileResponse
 	if previewImage != nil {
-		preview = &FileResponse{
-			Id:  previewImage.Id,
-			Url: storage.GetUrl(previewImage.Filename)}
-
+		response := newFileResponse(*previewImage)
+		preview = &response
 	}
 
 	postImages := make([]FileResponse, 0, len(post.PostImages))
+	imageUrls := make(map[uint]string, len(post.PostImages))
 	for _, f := range post.PostImages {
-		postImages = append(postImages, FileResponse{
-			Id:  f.Id,
-			Url: storage.GetUrl(f.Filename),
-		})
+		image := newFileResponse(f)
+		postImages = append(postImages, image)
+		imageUrls[f.Id] = image.Url
 	}
 
 	postFiles := make([]FileResponse, 0, len(post.PostFiles))
 	for _, f := range post.PostFiles {
-		postFiles = append(postFiles, FileResponse{
-			Id:  f.Id,
-			Url: storage.GetUrl(f.Filename),
-		})
+		postFiles = append(postFiles, newFileResponse(f))
 	}
 
 	return PostResponse{
@@ -1122,10 +1520,17 @@ func ConvertPostToResponse(post models.Post, previewImage *models.File) PostResp
 		ChannelId:     post.ChannelId,
 		PreviewImage:  preview,
 		Title:         post.Title,
+		Slug:          post.Slug,
 		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		ContentHtml:   render.HTML(post.Content, post.ContentFormat, imageUrls),
 		LikesCount:    post.LikesCount,
 		DislikesCount: post.DislikesCount,
 		ViewsCount:    post.ViewsCount,
+		IsHidden:      post.IsHidden,
+		Status:        post.Status,
+		PublishAt:     post.PublishAt,
+		PublishedAt:   post.PublishedAt,
 		PostImages:    postImages,
 		PostFiles:     postFiles,
 		Tags:          post.Tags,
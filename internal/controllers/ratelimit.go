@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"time"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimit ограничивает частоту запросов к эндпоинту с одного IP
+func RateLimit(limiter *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := checkRateLimit(c, limiter, c.IP()); err != nil {
+			return c.Status(err.Code).JSON(ErrorResponse{
+				Message: err.Message,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// checkRateLimit учитывает запрос с ключом key и выставляет заголовки X-RateLimit-*.
+// Если к запросу применяется несколько лимитов, в заголовках остается самый строгий.
+func checkRateLimit(c *fiber.Ctx, limiter *ratelimit.Limiter, key string) *fiber.Error {
+	result := limiter.Allow(key)
+	resetIn := max(int(time.Until(result.ResetAt).Seconds()+0.5), 1)
+
+	current, err := strconv.Atoi(string(c.Response().Header.Peek(headerRateLimitRemaining)))
+	if err != nil || result.Remaining <= current {
+		c.Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Set(headerRateLimitReset, strconv.Itoa(resetIn))
+	}
+
+	if !result.Allowed {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(resetIn))
+		return fiber.NewError(fiber.StatusTooManyRequests, "Too many requests, try again later")
+	}
+	return nil
+}
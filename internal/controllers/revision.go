@@ -0,0 +1,419 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/pagination"
+	"blogpoint-backend/internal/repository"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"gorm.io/gorm"
+	"slices"
+	"strconv"
+)
+
+// GetPostRevisions возвращает историю изменений поста
+// @Summary      История изменений поста
+// @Description  Возвращает ревизии поста с полным содержимым, новые сначала, с курсорной пагинацией. Доступно тем, кто может управлять постами канала.
+// @Tags         Post
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id      path      int    true  "Id поста"
+// @Param        cursor  query     string false "Курсор из nextCursor предыдущей страницы"
+// @Param        limit   query     int    false "Размер страницы (по умолчанию 10, максимум 50)"
+// @Success      200     {object}  PageResponse[[]PostRevisionResponse]
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      403     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/getPostRevisions/{id} [get]
+func GetPostRevisions(c *fiber.Ctx) error {
+	postId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || postId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid post id"})
+	}
+
+	params, err := parsePageParams(c, pagination.ByTime)
+	if err != nil {
+		return invalidPageParams(c, err)
+	}
+
+	if _, _, failure := managedPost(CurrentUser(c), uint(postId)); failure != nil {
+		return c.Status(failure.Code).JSON(ErrorResponse{Message: failure.Message})
+	}
+
+	query := repository.DB.Where("post_id = ?", postId)
+	if params.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *params.Cursor.Time, params.Cursor.Id)
+	}
+
+	var revisions []models.PostRevision
+	if err = query.Order("created_at DESC, id DESC").Limit(params.Limit + 1).Find(&revisions).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to fetch revisions",
+		})
+	}
+
+	revisions, hasMore := pagination.Trim(revisions, params.Limit)
+
+	authorIds := make([]uint, 0, len(revisions))
+	for _, revision := range revisions {
+		if revision.AuthorId != nil {
+			authorIds = append(authorIds, *revision.AuthorId)
+		}
+	}
+
+	logins := make(map[uint]string)
+	if len(authorIds) > 0 {
+		var authors []models.User
+		repository.DB.Select("id", "login").Where("id IN ?", authorIds).Find(&authors)
+		for _, author := range authors {
+			logins[author.Id] = author.Login
+		}
+	}
+
+	response := make([]PostRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		item := PostRevisionResponse{
+			Id:        revision.Id,
+			PostId:    revision.PostId,
+			AuthorId:  revision.AuthorId,
+			Snapshot:  revision.Snapshot,
+			CreatedAt: revision.CreatedAt,
+		}
+		if revision.AuthorId != nil {
+			item.AuthorLogin = logins[*revision.AuthorId]
+		}
+		response = append(response, item)
+	}
+
+	var last pagination.Cursor
+	if len(revisions) > 0 {
+		lastRevision := revisions[len(revisions)-1]
+		last = pagination.TimeCursor(lastRevision.CreatedAt, lastRevision.Id)
+	}
+
+	return c.JSON(newPageResponse(response, hasMore, last))
+}
+
+// GetPostRevisionDiff сравнивает две ревизии поста
+// @Summary      Сравнение ревизий поста
+// @Description  Возвращает разницу между двумя ревизиями поста: заголовок сравнивается посимвольно, текст — построчно, для тегов, изображений и файлов возвращаются добавленные и удаленные Id. Если to не указан, сравнение идет с последней ревизией. Доступно тем, кто может управлять постами канала.
+// @Tags         Post
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id    path      int true  "Id поста"
+// @Param        from  query     int true  "Id исходной ревизии"
+// @Param        to    query     int false "Id конечной ревизии"
+// @Success      200   {object}  DataResponse[PostRevisionDiffResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/getPostRevisionDiff/{id} [get]
+func GetPostRevisionDiff(c *fiber.Ctx) error {
+	postId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || postId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid post id"})
+	}
+
+	fromId, err := strconv.ParseUint(c.Query("from"), 10, 64)
+	if err != nil || fromId == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid from revision id"})
+	}
+
+	var toId uint64
+	if to := c.Query("to"); to != "" {
+		toId, err = strconv.ParseUint(to, 10, 64)
+		if err != nil || toId == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid to revision id"})
+		}
+	}
+
+	if _, _, failure := managedPost(CurrentUser(c), uint(postId)); failure != nil {
+		return c.Status(failure.Code).JSON(ErrorResponse{Message: failure.Message})
+	}
+
+	var from models.PostRevision
+	if err = repository.DB.Where("id = ? AND post_id = ?", fromId, postId).First(&from).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Revision not found",
+		})
+	}
+
+	toQuery := repository.DB.Where("post_id = ?", postId)
+	if toId != 0 {
+		toQuery = toQuery.Where("id = ?", toId)
+	} else {
+		toQuery = toQuery.Order("created_at DESC, id DESC")
+	}
+
+	var to models.PostRevision
+	if err = toQuery.First(&to).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Revision not found",
+		})
+	}
+
+	return c.JSON(DataResponse[PostRevisionDiffResponse]{
+		Data: PostRevisionDiffResponse{
+			FromId:         from.Id,
+			ToId:           to.Id,
+			Title:          diffText(from.Snapshot.Title, to.Snapshot.Title, false),
+			Content:        diffText(from.Snapshot.Content, to.Snapshot.Content, true),
+			PreviewImageId: diffIds(optionalId(from.Snapshot.PreviewImageId), optionalId(to.Snapshot.PreviewImageId)),
+			Tags:           diffIds(from.Snapshot.Tags, to.Snapshot.Tags),
+			PostImages:     diffIds(from.Snapshot.PostImages, to.Snapshot.PostImages),
+			PostFiles:      diffIds(from.Snapshot.PostFiles, to.Snapshot.PostFiles),
+		},
+	})
+}
+
+// RestorePostRevision восстанавливает пост из ревизии
+// @Summary      Восстановление ревизии поста
+// @Description  Возвращает посту заголовок, текст, превью, теги, изображения и файлы из выбранной ревизии. Удаленные с тех пор файлы и теги пропускаются. Восстановление сохраняется как новая ревизия. Доступно тем, кто может управлять постами канала.
+// @Tags         Post
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        data  body      RestorePostRevisionRequest true "Id поста и ревизии"
+// @Success      200   {object}  DataResponse[PostResponse]
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/restorePostRevision [post]
+func RestorePostRevision(c *fiber.Ctx) error {
+	var data RestorePostRevisionRequest
+
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	user := CurrentUser(c)
+
+	post, channel, failure := managedPost(user, data.PostId)
+	if failure != nil {
+		return c.Status(failure.Code).JSON(ErrorResponse{Message: failure.Message})
+	}
+
+	var revision models.PostRevision
+	if err := repository.DB.Where("id = ? AND post_id = ?", data.RevisionId, post.Id).First(&revision).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Revision not found",
+		})
+	}
+
+	snapshot := revision.Snapshot
+
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, post.Id); err != nil {
+			return err
+		}
+
+		if snapshot.Title != post.Title {
+			post.Title = snapshot.Title
+			if err := updatePostSlug(tx, &post); err != nil {
+				return err
+			}
+		}
+		post.Content = snapshot.Content
+		// Ревизии, сохраненные до появления форматов, не содержат формат
+		if snapshot.ContentFormat != "" {
+			post.ContentFormat = snapshot.ContentFormat
+		}
+		post.PreviewImageId = nil
+		if snapshot.PreviewImageId != nil {
+			var count int64
+			tx.Model(&models.File{}).Where("id = ?", *snapshot.PreviewImageId).Count(&count)
+			if count > 0 {
+				post.PreviewImageId = snapshot.PreviewImageId
+			}
+		}
+
+		if err := tx.Model(&post).Updates(map[string]interface{}{
+			"title":            post.Title,
+			"slug":             post.Slug,
+			"content":          post.Content,
+			"content_format":   post.ContentFormat,
+			"preview_image_id": post.PreviewImageId,
+		}).Error; err != nil {
+			return err
+		}
+
+		var tags []models.Tag
+		if err := tx.Where("id IN ?", snapshot.Tags).Find(&tags).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&post).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+
+		var postImages []models.File
+		if err := tx.Where("id IN ?", snapshot.PostImages).Find(&postImages).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&post).Association("PostImages").Replace(postImages); err != nil {
+			return err
+		}
+
+		var postFiles []models.File
+		if err := tx.Where("id IN ?", snapshot.PostFiles).Find(&postFiles).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&post).Association("PostFiles").Replace(postFiles); err != nil {
+			return err
+		}
+
+		return savePostRevision(tx, post.Id, &user.Id)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to restore revision",
+		})
+	}
+
+	if user.Id != channel.OwnerId {
+		logModeration(user, channel.Id, actionEditPost, "post", post.Id, fmt.Sprintf("Restored revision %d", revision.Id))
+	}
+
+	if err = repository.DB.Preload("Tags").Preload("PostImages").Preload("PostFiles").First(&post, post.Id).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to load post",
+		})
+	}
+
+	var previewFile *models.File
+	if post.PreviewImageId != nil {
+		var file models.File
+		if err := repository.DB.First(&file, *post.PreviewImageId).Error; err == nil {
+			previewFile = &file
+		}
+	}
+
+	return c.JSON(DataResponse[PostResponse]{
+		Data:    ConvertPostToResponse(post, previewFile),
+		Message: "Revision restored successfully",
+	})
+}
+
+// managedPost загружает пост и его канал и проверяет, что пользователь может управлять постом.
+// Ошибка содержит код и текст ответа.
+func managedPost(user *models.User, postId uint) (models.Post, models.Channel, *fiber.Error) {
+	var post models.Post
+	var channel models.Channel
+
+	if err := repository.DB.First(&post, postId).Error; err != nil {
+		return post, channel, fiber.NewError(fiber.StatusNotFound, "Post not found")
+	}
+
+	if err := repository.DB.First(&channel, post.ChannelId).Error; err != nil {
+		return post, channel, fiber.NewError(fiber.StatusInternalServerError, "Channel not found")
+	}
+
+	if !canManagePost(user, &channel) {
+		return post, channel, fiber.NewError(fiber.StatusForbidden, "You are not allowed to manage this post")
+	}
+
+	return post, channel, nil
+}
+
+// savePostRevision сохраняет текущее состояние поста как новую ревизию
+func savePostRevision(tx *gorm.DB, postId uint, authorId *uint) error {
+	var post models.Post
+	if err := tx.Preload("Tags").Preload("PostImages").Preload("PostFiles").First(&post, postId).Error; err != nil {
+		return err
+	}
+
+	snapshot := models.PostSnapshot{
+		Title:          post.Title,
+		Content:        post.Content,
+		ContentFormat:  post.ContentFormat,
+		PreviewImageId: post.PreviewImageId,
+		Tags:           make([]uint, 0, len(post.Tags)),
+		PostImages:     make([]uint, 0, len(post.PostImages)),
+		PostFiles:      make([]uint, 0, len(post.PostFiles)),
+	}
+	for _, tag := range post.Tags {
+		snapshot.Tags = append(snapshot.Tags, tag.Id)
+	}
+	for _, file := range post.PostImages {
+		snapshot.PostImages = append(snapshot.PostImages, file.Id)
+	}
+	for _, file := range post.PostFiles {
+		snapshot.PostFiles = append(snapshot.PostFiles, file.Id)
+	}
+
+	return tx.Create(&models.PostRevision{
+		PostId:   post.Id,
+		AuthorId: authorId,
+		Snapshot: snapshot,
+	}).Error
+}
+
+// ensureBaselineRevision сохраняет исходное состояние поста без автора, если у поста еще нет ревизий.
+// Нужно для постов, созданных до появления истории изменений, чтобы первую правку можно было откатить.
+func ensureBaselineRevision(tx *gorm.DB, postId uint) error {
+	var count int64
+	if err := tx.Model(&models.PostRevision{}).Where("post_id = ?", postId).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return savePostRevision(tx, postId, nil)
+}
+
+// diffText сравнивает две строки. В построчном режиме каждая строка — отдельный кусок,
+// иначе сравнение посимвольное с объединением мелких правок.
+func diffText(from string, to string, byLines bool) []DiffChunk {
+	dmp := diffmatchpatch.New()
+
+	var diffs []diffmatchpatch.Diff
+	if byLines {
+		fromChars, toChars, lines := dmp.DiffLinesToChars(from, to)
+		diffs = dmp.DiffCharsToLines(dmp.DiffMain(fromChars, toChars, false), lines)
+	} else {
+		diffs = dmp.DiffCleanupSemantic(dmp.DiffMain(from, to, false))
+	}
+
+	chunks := make([]DiffChunk, 0, len(diffs))
+	for _, d := range diffs {
+		op := "equal"
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = "insert"
+		case diffmatchpatch.DiffDelete:
+			op = "delete"
+		}
+		chunks = append(chunks, DiffChunk{Op: op, Text: d.Text})
+	}
+	return chunks
+}
+
+// diffIds возвращает Id, которые есть только в to (добавлены) и только в from (удалены)
+func diffIds(from []uint, to []uint) IdsDiff {
+	result := IdsDiff{Added: make([]uint, 0), Removed: make([]uint, 0)}
+	for _, id := range to {
+		if !slices.Contains(from, id) {
+			result.Added = append(result.Added, id)
+		}
+	}
+	for _, id := range from {
+		if !slices.Contains(to, id) {
+			result.Removed = append(result.Removed, id)
+		}
+	}
+	return result
+}
+
+func optionalId(id *uint) []uint {
+	if id == nil {
+		return nil
+	}
+	return []uint{*id}
+}
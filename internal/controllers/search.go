@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"blogpoint-backend/internal/search"
+	"github.com/gofiber/fiber/v2"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const searchPageSize = 10
+
+// Search ищет посты и каналы по тексту
+// @Summary      Поиск
+// @Description  Полнотекстовый поиск по заголовкам и тексту постов, названиям и описаниям каналов с учетом морфологии. Язык берется из параметра lang, иначе из профиля пользователя, по умолчанию ru. Результаты отсортированы по релевантности, совпадения во фрагментах выделены тегом mark.
+// @Tags         Search
+// @Produce      json
+// @Param        q           query     string true  "Поисковый запрос (поддерживает кавычки, OR и минус)"
+// @Param        type        query     string false "Что искать: all, posts, channels (по умолчанию all)"
+// @Param        lang        query     string false "Язык морфологии: ru или en"
+// @Param        categoryId  query     int    false "Id категории"
+// @Param        tagId       query     int    false "Id тега (только посты)"
+// @Param        channelId   query     int    false "Id канала (только посты)"
+// @Param        from        query     string false "Начало периода публикации, RFC 3339 или YYYY-MM-DD"
+// @Param        to          query     string false "Конец периода публикации (не включая), RFC 3339 или YYYY-MM-DD"
+// @Param        page        query     int    false "Номер страницы (по умолчанию 1)"
+// @Success      200         {object}  DataResponse[SearchResponse]
+// @Failure      400         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /api/search [get]
+func Search(c *fiber.Ctx, engine search.Engine) error {
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Search query is required",
+		})
+	}
+
+	searchType := c.Query("type", "all")
+	if searchType != "all" && searchType != "posts" && searchType != "channels" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid search type",
+		})
+	}
+
+	// Поиск не переведен на курсоры: посты и каналы ранжируются независимо и возвращаются в одном ответе,
+	// поэтому общая позиция задается номером страницы, одинаковым для обоих списков
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid page value",
+		})
+	}
+
+	language := c.Query("lang")
+	if language == "" {
+		if user := CurrentUser(c); user != nil {
+			language = user.Language
+		}
+	}
+
+	query := search.Query{
+		Text:     text,
+		Language: search.NormalizeLanguage(language),
+		Limit:    searchPageSize,
+		Offset:   (page - 1) * searchPageSize,
+	}
+
+	for param, target := range map[string]**uint{
+		"categoryId": &query.CategoryId,
+		"tagId":      &query.TagId,
+		"channelId":  &query.ChannelId,
+	} {
+		if value := c.Query(param); value != "" {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil || id == 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+					Message: "Invalid " + param,
+				})
+			}
+			parsed := uint(id)
+			*target = &parsed
+		}
+	}
+
+	for param, target := range map[string]**time.Time{
+		"from": &query.From,
+		"to":   &query.To,
+	} {
+		if value := c.Query(param); value != "" {
+			parsed, err := parseSearchDate(value)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+					Message: "Invalid " + param + " date",
+				})
+			}
+			*target = &parsed
+		}
+	}
+
+	response := SearchResponse{
+		Posts:    make([]PostSearchResult, 0),
+		Channels: make([]ChannelSearchResult, 0),
+	}
+
+	if searchType != "channels" {
+		hits, err := engine.SearchPosts(c.Context(), query)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Message: "Failed to search posts",
+			})
+		}
+		if response.Posts, err = buildPostSearchResults(hits); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Message: "Failed to fetch posts",
+			})
+		}
+	}
+
+	// Фильтры по тегу и каналу относятся только к постам
+	if searchType != "posts" && query.TagId == nil && query.ChannelId == nil {
+		hits, err := engine.SearchChannels(c.Context(), query)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Message: "Failed to search channels",
+			})
+		}
+		if response.Channels, err = buildChannelSearchResults(hits); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Message: "Failed to fetch channels",
+			})
+		}
+	}
+
+	return c.JSON(DataResponse[SearchResponse]{
+		Data: response,
+	})
+}
+
+func buildPostSearchResults(hits []search.PostHit) ([]PostSearchResult, error) {
+	results := make([]PostSearchResult, 0, len(hits))
+	if len(hits) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.PostId
+	}
+
+	var posts []models.Post
+	if err := repository.DB.Preload("Tags").Preload("PostImages").Preload("PostFiles").
+		Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	postMap := make(map[uint]models.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.Id] = post
+	}
+
+	for _, hit := range hits {
+		post, ok := postMap[hit.PostId]
+		if !ok {
+			continue
+		}
+
+		var preview *models.File
+		if post.PreviewImageId != nil {
+			var file models.File
+			if err := repository.DB.First(&file, *post.PreviewImageId).Error; err == nil {
+				preview = &file
+			}
+		}
+
+		results = append(results, PostSearchResult{
+			Post:    ConvertPostToResponse(post, preview),
+			Snippet: highlightSnippet(hit.Snippet),
+			Rank:    hit.Rank,
+		})
+	}
+
+	return results, nil
+}
+
+func buildChannelSearchResults(hits []search.ChannelHit) ([]ChannelSearchResult, error) {
+	results := make([]ChannelSearchResult, 0, len(hits))
+	if len(hits) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ChannelId
+	}
+
+	var channels []models.Channel
+	if err := repository.DB.Preload("Category").Where("id IN ?", ids).Find(&channels).Error; err != nil {
+		return nil, err
+	}
+
+	channelMap := make(map[uint]models.Channel, len(channels))
+	for _, channel := range channels {
+		channelMap[channel.Id] = channel
+	}
+
+	for _, hit := range hits {
+		channel, ok := channelMap[hit.ChannelId]
+		if !ok {
+			continue
+		}
+
+		var logo *models.File
+		if channel.LogoId != nil {
+			var file models.File
+			if err := repository.DB.First(&file, *channel.LogoId).Error; err == nil {
+				logo = &file
+			}
+		}
+
+		results = append(results, ChannelSearchResult{
+			Channel: ConvertChannelToResponse(channel, logo),
+			Snippet: highlightSnippet(hit.Snippet),
+			Rank:    hit.Rank,
+		})
+	}
+
+	return results, nil
+}
+
+// highlightSnippet экранирует фрагмент и заменяет маркеры движка на тег mark
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, search.HighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, search.HighlightStop, "</mark>")
+}
+
+func parseSearchDate(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
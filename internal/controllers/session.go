@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/config"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Как часто обновлять last_seen_at сессии при обычных запросах
+	lastSeenInterval = time.Minute
+	// refreshRotationGrace — сколько после ротации старый refresh токен считается проигравшим гонку
+	// параллельного обновления (например, из двух вкладок), а не украденным
+	refreshRotationGrace = 30 * time.Second
+
+	accessCookieName  = "jwt"
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/api"
+)
+
+// authConfig задается через Configure при старте приложения
+var authConfig config.AuthConfig
+
+var (
+	errInvalidRefreshToken = errors.New("invalid refresh token")
+	errRefreshTokenReuse   = errors.New("refresh token reuse detected")
+	errRefreshTokenRotated = errors.New("refresh token was just rotated")
+)
+
+// Configure задает секрет подписи JWT и время жизни токенов
+func Configure(cfg config.AuthConfig) {
+	authConfig = cfg
+}
+
+// startSession создает серверную сессию для пользователя и выставляет cookie с access и refresh токенами
+func startSession(c *fiber.Ctx, userId uint) (*models.Session, error) {
+	secret, err := generateRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		UserId:           userId,
+		RefreshTokenHash: hashRefreshSecret(secret),
+		UserAgent:        c.Get(fiber.HeaderUserAgent),
+		Ip:               c.IP(),
+		CreatedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(authConfig.RefreshTokenTTL),
+	}
+
+	if err = repository.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	if err = setAuthCookies(c, &session, secret); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// rotateSession проверяет refresh токен и выдает новую пару токенов.
+// Предъявление уже использованного refresh токена этой сессии считается кражей: сессия отзывается.
+// Исключение — токен, замененный не раньше refreshRotationGrace назад: это параллельное обновление
+// того же клиента, оно отклоняется без отзыва. Токен, который сессия никогда не выдавала, тоже просто
+// отклоняется, иначе по последовательным id сессий можно было бы отозвать чужие сессии.
+func rotateSession(c *fiber.Ctx, refreshToken string) (*models.Session, error) {
+	sessionId, secret, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, errInvalidRefreshToken
+	}
+
+	var session models.Session
+	if err := repository.DB.First(&session, sessionId).Error; err != nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	if !isSessionActive(&session) {
+		return nil, errInvalidRefreshToken
+	}
+
+	secretHash := hashRefreshSecret(secret)
+	if subtle.ConstantTimeCompare([]byte(session.RefreshTokenHash), []byte(secretHash)) != 1 {
+		var used models.UsedRefreshToken
+		if err := repository.DB.
+			Where("session_id = ? AND token_hash = ?", session.Id, secretHash).
+			Take(&used).Error; err != nil {
+			return nil, errInvalidRefreshToken
+		}
+		if time.Since(used.CreatedAt) < refreshRotationGrace {
+			return nil, errRefreshTokenRotated
+		}
+		revokeSession(&session)
+		return nil, errRefreshTokenReuse
+	}
+
+	newSecret, err := generateRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rotated := true
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		// Условие на старый хеш защищает от гонки двух одновременных обновлений одним токеном
+		result := tx.Model(&session).
+			Where("refresh_token_hash = ?", session.RefreshTokenHash).
+			Updates(map[string]interface{}{
+				"refresh_token_hash": hashRefreshSecret(newSecret),
+				"user_agent":         c.Get(fiber.HeaderUserAgent),
+				"ip":                 c.IP(),
+				"last_seen_at":       now,
+				"expires_at":         now.Add(authConfig.RefreshTokenTTL),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			rotated = false
+			return nil
+		}
+
+		// Запоминаем замененный токен, чтобы распознать его повторное предъявление
+		return tx.Create(&models.UsedRefreshToken{SessionId: session.Id, TokenHash: session.RefreshTokenHash}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Токен успели заменить между проверкой и обновлением: параллельный запрос того же клиента
+	if !rotated {
+		return nil, errRefreshTokenRotated
+	}
+
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(authConfig.RefreshTokenTTL)
+
+	if err = setAuthCookies(c, &session, newSecret); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func isSessionActive(session *models.Session) bool {
+	return session.RevokedAt == nil && session.ExpiresAt.After(time.Now())
+}
+
+// revokeSession помечает сессию отозванной
+func revokeSession(session *models.Session) error {
+	now := time.Now()
+	return repository.DB.Model(session).Update("revoked_at", now).Error
+}
+
+// revokeUserSessions отзывает все активные сессии пользователя, кроме exceptSessionId (0 — отозвать все)
+func revokeUserSessions(userId uint, exceptSessionId uint) (int64, error) {
+	query := repository.DB.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId)
+	if exceptSessionId != 0 {
+		query = query.Where("id <> ?", exceptSessionId)
+	}
+
+	result := query.Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// findSessionByRefreshToken находит сессию по refresh токену без его ротации
+func findSessionByRefreshToken(refreshToken string) (*models.Session, error) {
+	sessionId, secret, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, errInvalidRefreshToken
+	}
+
+	var session models.Session
+	if err := repository.DB.First(&session, sessionId).Error; err != nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	if subtle.ConstantTimeCompare([]byte(session.RefreshTokenHash), []byte(hashRefreshSecret(secret))) != 1 {
+		return nil, errInvalidRefreshToken
+	}
+
+	return &session, nil
+}
+
+func setAuthCookies(c *fiber.Ctx, session *models.Session, refreshSecret string) error {
+	accessExpiresAt := time.Now().Add(authConfig.AccessTokenTTL)
+
+	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": strconv.Itoa(int(session.UserId)),
+		"sid": strconv.Itoa(int(session.Id)),
+		"exp": accessExpiresAt.Unix(),
+	})
+
+	token, err := claims.SignedString([]byte(authConfig.JWTSecret))
+	if err != nil {
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     accessCookieName,
+		Value:    token,
+		Expires:  accessExpiresAt,
+		Secure:   authConfig.SecureCookies,
+		HTTPOnly: true,
+	})
+
+	c.Cookie(&fiber.Cookie{
+		Name:     refreshCookieName,
+		Value:    strconv.Itoa(int(session.Id)) + "." + refreshSecret,
+		Path:     refreshCookiePath,
+		Expires:  session.ExpiresAt,
+		Secure:   authConfig.SecureCookies,
+		HTTPOnly: true,
+	})
+
+	return nil
+}
+
+func ConvertSessionToResponse(session models.Session, currentSessionId uint) SessionResponse {
+	return SessionResponse{
+		Id:         session.Id,
+		UserAgent:  session.UserAgent,
+		Ip:         session.Ip,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		IsCurrent:  session.Id == currentSessionId,
+	}
+}
+
+func clearAuthCookies(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     accessCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		HTTPOnly: true,
+	})
+
+	c.Cookie(&fiber.Cookie{
+		Name:     refreshCookieName,
+		Value:    "",
+		Path:     refreshCookiePath,
+		Expires:  time.Now().Add(-time.Hour),
+		HTTPOnly: true,
+	})
+}
+
+func generateRefreshSecret() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+func hashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseRefreshToken разбирает токен вида "<id сессии>.<секрет>"
+func parseRefreshToken(refreshToken string) (uint, string, bool) {
+	strId, secret, found := strings.Cut(refreshToken, ".")
+	if !found || secret == "" {
+		return 0, "", false
+	}
+
+	sessionId, err := strconv.ParseUint(strId, 10, 32)
+	if err != nil || sessionId == 0 {
+		return 0, "", false
+	}
+
+	return uint(sessionId), secret, true
+}
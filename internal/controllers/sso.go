@@ -0,0 +1,532 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"blogpoint-backend/internal/sso"
+	"crypto/subtle"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	oidcStateCookieName = "oidc_state"
+	oidcStateCookiePath = "/api/oidc"
+	oidcStateTTL        = 10 * time.Minute
+	oidcStateType       = "oidc_state"
+
+	// oidcLoginAttempts — сколько логинов с случайным суффиксом перебрать для нового пользователя
+	oidcLoginAttempts = 5
+)
+
+var (
+	errIdentityTaken    = errors.New("identity is linked to another user")
+	errEmailNotVerified = errors.New("email is not verified")
+	errEmailRequired    = errors.New("provider did not return an email")
+)
+
+// oidcState — то, что нужно сохранить между редиректом к провайдеру и возвратом на callback.
+// Хранится в подписанной cookie, поэтому отдельная таблица не нужна.
+type oidcState struct {
+	Provider   string
+	State      string
+	Nonce      string
+	Verifier   string
+	LinkUserId uint
+}
+
+// GetOIDCProviders возвращает провайдеров для входа
+// @Summary      Провайдеры входа
+// @Description  Возвращает настроенных OIDC провайдеров и адреса, с которых начинается вход
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  DataResponse[[]OIDCProviderResponse]
+// @Router       /api/oidc/providers [get]
+func GetOIDCProviders(c *fiber.Ctx, providers *sso.Registry) error {
+	response := make([]OIDCProviderResponse, 0, len(providers.Providers()))
+	for _, provider := range providers.Providers() {
+		response = append(response, OIDCProviderResponse{
+			Name:        provider.Name(),
+			DisplayName: provider.DisplayName(),
+			LoginUrl:    "/api/oidc/" + provider.Name() + "/login",
+		})
+	}
+
+	return c.JSON(DataResponse[[]OIDCProviderResponse]{
+		Data: response,
+	})
+}
+
+// OIDCLogin перенаправляет на страницу входа провайдера
+// @Summary      Вход через провайдера
+// @Description  Перенаправляет браузер к OIDC провайдеру (authorization code flow с PKCE). С параметром link=true аккаунт провайдера привязывается к текущему пользователю, для этого нужна авторизация
+// @Tags         Auth
+// @Param        provider  path   string  true   "Имя провайдера"
+// @Param        link      query  bool    false  "Привязать к текущему пользователю"
+// @Success      302
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      502  {object}  ErrorResponse
+// @Router       /api/oidc/{provider}/login [get]
+func OIDCLogin(c *fiber.Ctx, providers *sso.Registry) error {
+	provider, err := providers.Get(c.Params("provider"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Provider not found",
+		})
+	}
+
+	state := oidcState{
+		Provider: provider.Name(),
+		Verifier: sso.GenerateVerifier(),
+	}
+
+	if c.QueryBool("link") {
+		user := CurrentUser(c)
+		if user == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
+				Message: "Unauthenticated",
+			})
+		}
+		state.LinkUserId = user.Id
+	}
+
+	if state.State, err = generateRefreshSecret(); err != nil {
+		return err
+	}
+	if state.Nonce, err = generateRefreshSecret(); err != nil {
+		return err
+	}
+
+	authURL, err := provider.AuthCodeURL(c.UserContext(), state.State, state.Nonce, state.Verifier)
+	if err != nil {
+		log.Printf("Провайдер %s недоступен: %v", provider.Name(), err)
+		return c.Status(fiber.StatusBadGateway).JSON(ErrorResponse{
+			Message: "Provider is unavailable",
+		})
+	}
+
+	value, err := signOidcState(state)
+	if err != nil {
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    value,
+		Path:     oidcStateCookiePath,
+		Expires:  time.Now().Add(oidcStateTTL),
+		Secure:   authConfig.SecureCookies,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// OIDCCallback завершает вход через провайдера
+// @Summary      Возврат от провайдера
+// @Description  Обменивает код авторизации на ID токен и перенаправляет на страницу фронтенда. Пользователь находится по привязанному аккаунту провайдера или по подтвержденному провайдером email, иначе создается новый. При успехе выставляются cookie сессии; если включена двухфакторная аутентификация, в адресе передается mfaToken для /api/loginMfa, при ошибке — параметр error
+// @Tags         Auth
+// @Param        provider  path   string  true  "Имя провайдера"
+// @Param        code      query  string  true  "Код авторизации"
+// @Param        state     query  string  true  "Состояние из запроса входа"
+// @Success      302
+// @Failure      404  {object}  ErrorResponse
+// @Router       /api/oidc/{provider}/callback [get]
+func OIDCCallback(c *fiber.Ctx, providers *sso.Registry) error {
+	provider, err := providers.Get(c.Params("provider"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Provider not found",
+		})
+	}
+
+	state, ok := parseOidcState(c.Cookies(oidcStateCookieName))
+	clearOidcStateCookie(c)
+	if !ok || state.Provider != provider.Name() ||
+		subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(state.State)) != 1 {
+		return oidcRedirect(c, providers, url.Values{"error": {"invalid_state"}})
+	}
+
+	if c.Query("error") != "" {
+		return oidcRedirect(c, providers, url.Values{"error": {"access_denied"}})
+	}
+
+	claims, err := provider.Exchange(c.UserContext(), c.Query("code"), state.Verifier, state.Nonce)
+	if err != nil {
+		log.Printf("Не удалось завершить вход через %s: %v", provider.Name(), err)
+		return oidcRedirect(c, providers, url.Values{"error": {"exchange_failed"}})
+	}
+
+	if state.LinkUserId != 0 {
+		user := CurrentUser(c)
+		if user == nil || user.Id != state.LinkUserId {
+			return oidcRedirect(c, providers, url.Values{"error": {"invalid_state"}})
+		}
+		if err = linkIdentity(user, provider.Name(), claims); err != nil {
+			return oidcRedirect(c, providers, url.Values{"error": {oidcErrorCode(err)}})
+		}
+		return oidcRedirect(c, providers, url.Values{"linked": {provider.Name()}})
+	}
+
+	user, err := resolveIdentityUser(provider.Name(), claims)
+	if err != nil {
+		return oidcRedirect(c, providers, url.Values{"error": {oidcErrorCode(err)}})
+	}
+
+	if user.TotpEnabled {
+		token, err := issueMfaToken(user.Id)
+		if err != nil {
+			return err
+		}
+		return oidcRedirect(c, providers, url.Values{"mfaToken": {token}})
+	}
+
+	resetFailedLogins(user)
+	if _, err = startSession(c, user.Id); err != nil {
+		return oidcRedirect(c, providers, url.Values{"error": {"login_failed"}})
+	}
+
+	return oidcRedirect(c, providers, nil)
+}
+
+// GetIdentities возвращает привязанные аккаунты провайдеров
+// @Summary      Привязанные аккаунты
+// @Description  Возвращает аккаунты OIDC провайдеров, через которые можно войти в аккаунт текущего пользователя
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  DataResponse[[]IdentityResponse]
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/getIdentities [get]
+func GetIdentities(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	var identities []models.Identity
+	if err := repository.DB.Where("user_id = ?", user.Id).Order("created_at").Find(&identities).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to retrieve identities",
+		})
+	}
+
+	response := make([]IdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		response = append(response, IdentityResponse{
+			Id:        identity.Id,
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	return c.JSON(DataResponse[[]IdentityResponse]{
+		Data: response,
+	})
+}
+
+// UnlinkIdentity отвязывает аккаунт провайдера
+// @Summary      Отвязка аккаунта провайдера
+// @Description  Отвязывает аккаунт OIDC провайдера от текущего пользователя. Пользователь, созданный через провайдера, может задать пароль через сброс пароля
+// @Tags         Auth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "ID привязки"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/unlinkIdentity/{id} [delete]
+func UnlinkIdentity(c *fiber.Ctx) error {
+	user := CurrentUser(c)
+
+	identityId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Invalid identity id",
+		})
+	}
+
+	result := repository.DB.Where("id = ? AND user_id = ?", identityId, user.Id).Delete(&models.Identity{})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Message: "Failed to unlink identity",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+			Message: "Identity not found",
+		})
+	}
+
+	return c.JSON(MessageResponse{
+		Message: "Identity unlinked",
+	})
+}
+
+// resolveIdentityUser находит пользователя для входа через провайдера: по привязке, по email или создает нового
+func resolveIdentityUser(provider string, claims *sso.Claims) (*models.User, error) {
+	var identity models.Identity
+	err := repository.DB.Where("provider = ? AND subject = ?", provider, claims.Subject).First(&identity).Error
+	if err == nil {
+		var user models.User
+		if err = repository.DB.First(&user, identity.UserId).Error; err != nil {
+			return nil, err
+		}
+		markEmailVerified(&user, claims)
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, errEmailRequired
+	}
+
+	var user models.User
+	err = repository.DB.Where("email = ?", claims.Email).First(&user).Error
+	switch {
+	case err == nil:
+		// Привязываем к существующему аккаунту только email, подтвержденный и провайдером, и у нас:
+		// иначе можно было бы указать у провайдера чужой адрес и войти в чужой аккаунт, либо заранее
+		// зарегистрировать аккаунт на чужой адрес и сохранить к нему доступ по паролю после привязки
+		if !claims.EmailVerified || !user.IsVerified {
+			return nil, errEmailNotVerified
+		}
+		if err = repository.DB.Create(newIdentity(user.Id, provider, claims)).Error; err != nil {
+			return nil, err
+		}
+		markEmailVerified(&user, claims)
+		return &user, nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Аккаунт с неподтвержденным email удалила бы очистка неподтвержденных аккаунтов,
+		// а кода подтверждения пользователь не получает, поэтому такой аккаунт не создаем
+		if !claims.EmailVerified {
+			return nil, errEmailNotVerified
+		}
+		return createIdentityUser(provider, claims)
+	default:
+		return nil, err
+	}
+}
+
+// linkIdentity привязывает аккаунт провайдера к пользователю
+func linkIdentity(user *models.User, provider string, claims *sso.Claims) error {
+	var identity models.Identity
+	err := repository.DB.Where("provider = ? AND subject = ?", provider, claims.Subject).First(&identity).Error
+	if err == nil {
+		if identity.UserId != user.Id {
+			return errIdentityTaken
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err = repository.DB.Create(newIdentity(user.Id, provider, claims)).Error; err != nil {
+		return err
+	}
+	markEmailVerified(user, claims)
+	return nil
+}
+
+// createIdentityUser регистрирует нового пользователя с подтвержденным провайдером email. Пароль случайный:
+// войти по паролю можно будет после его сброса через почту.
+func createIdentityUser(provider string, claims *sso.Claims) (*models.User, error) {
+	secret, err := generateRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+	password, err := bcrypt.GenerateFromPassword([]byte(secret), 14)
+	if err != nil {
+		return nil, err
+	}
+
+	language := "ru"
+	if strings.HasPrefix(strings.ToLower(claims.Locale), "en") {
+		language = "en"
+	}
+
+	user := models.User{
+		Email:      claims.Email,
+		Password:   password,
+		Language:   language,
+		IsVerified: true,
+	}
+
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		login, err := availableLogin(tx, claims)
+		if err != nil {
+			return err
+		}
+		user.Login = login
+
+		if err = tx.Select("Login", "Email", "Password", "Language", "IsVerified").Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(newIdentity(user.Id, provider, claims)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// availableLogin подбирает свободный логин из preferred_username или email провайдера
+func availableLogin(tx *gorm.DB, claims *sso.Claims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var builder strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '.' || r == '-' {
+			builder.WriteRune(r)
+		}
+	}
+	base = builder.String()
+	if len(base) < 3 {
+		base = "user"
+	}
+	base = base[:min(len(base), 40)]
+
+	login := base
+	for range oidcLoginAttempts {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("login = ?", login).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return login, nil
+		}
+
+		suffix, err := randomCode(6)
+		if err != nil {
+			return "", err
+		}
+		login = base + "_" + strings.ToLower(suffix)
+	}
+
+	return "", errors.New("failed to pick a free login")
+}
+
+func newIdentity(userId uint, provider string, claims *sso.Claims) *models.Identity {
+	return &models.Identity{
+		UserId:   userId,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+}
+
+// markEmailVerified подтверждает email пользователя, если провайдер подтвердил этот же адрес
+func markEmailVerified(user *models.User, claims *sso.Claims) {
+	if user.IsVerified || !claims.EmailVerified || !strings.EqualFold(user.Email, claims.Email) {
+		return
+	}
+
+	if err := repository.DB.Model(user).UpdateColumn("is_verified", true).Error; err != nil {
+		log.Printf("Не удалось подтвердить email пользователя %d: %v", user.Id, err)
+		return
+	}
+	user.IsVerified = true
+}
+
+func oidcErrorCode(err error) string {
+	switch {
+	case errors.Is(err, errIdentityTaken):
+		return "identity_taken"
+	case errors.Is(err, errEmailNotVerified):
+		return "email_not_verified"
+	case errors.Is(err, errEmailRequired):
+		return "email_required"
+	default:
+		log.Printf("Ошибка входа через провайдера: %v", err)
+		return "login_failed"
+	}
+}
+
+// oidcRedirect возвращает браузер на страницу фронтенда с параметрами результата
+func oidcRedirect(c *fiber.Ctx, providers *sso.Registry, params url.Values) error {
+	target, err := url.Parse(providers.FrontendURL())
+	if err != nil {
+		return err
+	}
+
+	query := target.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	target.RawQuery = query.Encode()
+
+	return c.Redirect(target.String(), fiber.StatusFound)
+}
+
+func signOidcState(state oidcState) (string, error) {
+	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ":   oidcStateType,
+		"prv":   state.Provider,
+		"state": state.State,
+		"nonce": state.Nonce,
+		"ver":   state.Verifier,
+		"uid":   strconv.Itoa(int(state.LinkUserId)),
+		"exp":   time.Now().Add(oidcStateTTL).Unix(),
+	})
+	return claims.SignedString([]byte(authConfig.JWTSecret))
+}
+
+func parseOidcState(value string) (oidcState, bool) {
+	if value == "" {
+		return oidcState{}, false
+	}
+
+	token, err := jwt.ParseWithClaims(value, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(authConfig.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return oidcState{}, false
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if typ, _ := claims["typ"].(string); typ != oidcStateType {
+		return oidcState{}, false
+	}
+
+	var state oidcState
+	state.Provider, _ = claims["prv"].(string)
+	state.State, _ = claims["state"].(string)
+	state.Nonce, _ = claims["nonce"].(string)
+	state.Verifier, _ = claims["ver"].(string)
+	strUserId, _ := claims["uid"].(string)
+	userId, err := strconv.ParseUint(strUserId, 10, 32)
+	if err != nil || state.State == "" || state.Nonce == "" || state.Verifier == "" {
+		return oidcState{}, false
+	}
+	state.LinkUserId = uint(userId)
+
+	return state, true
+}
+
+func clearOidcStateCookie(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    "",
+		Path:     oidcStateCookiePath,
+		Expires:  time.Now().Add(-time.Hour),
+		HTTPOnly: true,
+	})
+}
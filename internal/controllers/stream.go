@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/realtime"
+	"blogpoint-backend/internal/repository"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxStreamPosts ограничивает число постов, на которые можно подписаться одним соединением
+	maxStreamPosts = 20
+
+	// streamHeartbeat — интервал пустых комментариев, по которым прокси не закрывают соединение,
+	// а сервер узнает об отключении клиента
+	streamHeartbeat = 20 * time.Second
+)
+
+var (
+	errInvalidStreamPost  = errors.New("invalid post id")
+	errTooManyStreamPosts = errors.New("too many posts")
+)
+
+// Stream открывает поток событий в реальном времени (Server-Sent Events)
+// @Summary      Поток событий
+// @Description  Открывает поток Server-Sent Events. Для постов из параметра posts приходят события comment.created (новый комментарий, данные как в getPostComments) и reaction.updated (счетчики лайков и дизлайков). Авторизованный по jwt cookie пользователь также получает notification.created о своих новых уведомлениях. Клиент, который не успевает читать события, отключается событием overflow и должен переподключиться.
+// @Tags         Stream
+// @Produce      text/event-stream
+// @Param        posts  query     string false "Id постов через запятую (не больше 20)"
+// @Success      200    {string}  string "Поток событий"
+// @Failure      400    {object}  ErrorResponse
+// @Failure      403    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Router       /api/stream [get]
+func Stream(c *fiber.Ctx, hub *realtime.Hub) error {
+	user := CurrentUser(c)
+
+	postIds, err := parseStreamPosts(c.Query("posts"))
+	if err != nil {
+		message := "Invalid post id"
+		if errors.Is(err, errTooManyStreamPosts) {
+			message = fmt.Sprintf("Too many posts, maximum is %d", maxStreamPosts)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: message,
+		})
+	}
+
+	topics := make([]string, 0, len(postIds)+1)
+	if len(postIds) > 0 {
+		var posts []models.Post
+		if err = repository.DB.Select("id", "channel_id", "status", "is_hidden").
+			Where("id IN ?", postIds).Find(&posts).Error; err != nil || len(posts) != len(postIds) {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Message: "Post not found",
+			})
+		}
+
+		for _, post := range posts {
+			if !canViewPost(user, &post) {
+				return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+					Message: fmt.Sprintf("You are not allowed to view post %d", post.Id),
+				})
+			}
+			topics = append(topics, realtime.PostTopic(post.Id))
+		}
+	}
+
+	if user != nil {
+		topics = append(topics, realtime.UserTopic(user.Id))
+	}
+
+	if len(topics) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Message: "Nothing to subscribe to",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	subscription := hub.Subscribe(topics)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer hub.Unsubscribe(subscription)
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		// Первый комментарий сразу отправляет заголовки, чтобы клиент знал, что подписка готова
+		if writeStream(w, ": connected\n\n") != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-subscription.Events():
+				if !ok {
+					_ = writeStream(w, "event: overflow\ndata: {}\n\n")
+					return
+				}
+
+				payload, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				if writeStream(w, fmt.Sprintf("event: %s\ndata: %s\n\n", event.Type, payload)) != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if writeStream(w, ": ping\n\n") != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// parseStreamPosts разбирает список Id постов, убирая повторы
+func parseStreamPosts(value string) ([]uint, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			return nil, errInvalidStreamPost
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+
+	if len(ids) > maxStreamPosts {
+		return nil, errTooManyStreamPosts
+	}
+	return ids, nil
+}
+
+// writeStream отправляет клиенту кусок потока. Ошибка означает, что клиент отключился.
+func writeStream(w *bufio.Writer, chunk string) error {
+	if _, err := w.WriteString(chunk); err != nil {
+		return err
+	}
+	return w.Flush()
+}
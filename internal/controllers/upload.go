@@ -0,0 +1,544 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/media"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"blogpoint-backend/internal/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// uploadUrlTTL — срок действия ссылки; начатая до истечения срока загрузка не прерывается
+	uploadUrlTTL = time.Hour
+	// pendingUploadTTL — сколько загрузку можно подтвердить, после этого объект удаляется
+	pendingUploadTTL = 24 * time.Hour
+	// maxPendingUploads — сколько неподтвержденных загрузок может быть у пользователя одновременно
+	maxPendingUploads = 20
+
+	// chunkSize — размер части при загрузке через API. MinIO требует от всех частей, кроме последней, не меньше 5 МБ.
+	chunkSize = 8 << 20
+	// maxChunks — ограничение MinIO на число частей одной загрузки
+	maxChunks = 10000
+)
+
+// RequestUpload выдает ссылку для загрузки файла напрямую в хранилище
+// @Summary      Ссылка для загрузки файла
+// @Description  Возвращает подписанную ссылку для загрузки файла в хранилище запросом PUT, минуя API. Тип и размер файла входят в подпись: хранилище примет только файл с указанными Content-Type и Content-Length. Ссылка действует час, после загрузки ее нужно подтвердить через /api/completeUpload/{uploadId} в течение суток
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RequestUploadRequest  true  "Имя, размер и тип файла"
+// @Success      200      {object}  DataResponse[UploadUrlResponse]
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      413      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /api/requestUpload [post]
+func RequestUpload(c *fiber.Ctx, maxSize int64) error {
+	user := CurrentUser(c)
+
+	var data RequestUploadRequest
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	upload, fiberErr := newPendingUpload(c, user.Id, data, maxSize)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+
+	url, err := storage.PresignUpload(c.Context(), upload.Filename, upload.Size, upload.MimeType, uploadUrlTTL)
+	if errors.Is(err, storage.ErrPresignUnavailable) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(ErrorResponse{Message: "Direct uploads are not available"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error creating upload url"})
+	}
+
+	if err = repository.DB.Create(&upload).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving upload"})
+	}
+
+	return c.JSON(DataResponse[UploadUrlResponse]{
+		Data: UploadUrlResponse{
+			UploadId:  upload.Id,
+			Url:       url,
+			Method:    http.MethodPut,
+			Headers:   map[string]string{"Content-Type": data.MimeType},
+			ExpiresAt: time.Now().Add(uploadUrlTTL),
+		},
+	})
+}
+
+// CompleteUpload подтверждает загрузку файла по подписанной ссылке
+// @Summary      Подтверждение загрузки файла
+// @Description  Проверяет, что файл загружен в хранилище, его размер совпадает с заявленным, а тип по содержимому — с разрешенной категорией, и создает запись файла. Изображения обрабатываются как в /api/uploadFile. Файл, не прошедший проверку, удаляется; если файл еще не загружен, возвращается 409 и подтверждение можно повторить
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "Id загрузки"
+// @Success      200  {object}  DataResponse[FileResponse]
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      410  {object}  ErrorResponse
+// @Failure      413  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/completeUpload/{id} [post]
+func CompleteUpload(c *fiber.Ctx) error {
+	upload, fiberErr := findPendingUpload(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+	if upload.MultipartId != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Upload is chunked, complete it with /api/completeChunkedUpload"})
+	}
+
+	info, err := storage.StatFromMinIO(c.Context(), upload.Filename)
+	if err != nil {
+		if err.(*fiber.Error).Code == fiber.StatusNotFound {
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Message: "File has not been uploaded yet"})
+		}
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	return finishUpload(c, upload, info.Size)
+}
+
+// StartChunkedUpload начинает загрузку файла частями
+// @Summary      Начало загрузки частями
+// @Description  Начинает загрузку файла через API частями по chunkSize байт (последняя часть — остаток), чтобы прерванную загрузку можно было продолжить. Части отправляются через /api/uploadChunk/{uploadId}/{chunk}, загрузка завершается через /api/completeChunkedUpload/{uploadId} в течение суток
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RequestUploadRequest  true  "Имя, размер и тип файла"
+// @Success      200      {object}  DataResponse[ChunkedUploadResponse]
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      413      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /api/startChunkedUpload [post]
+func StartChunkedUpload(c *fiber.Ctx, maxSize int64) error {
+	user := CurrentUser(c)
+
+	var data RequestUploadRequest
+	if err := json.Unmarshal(c.Body(), &data); err != nil {
+		return err
+	}
+
+	upload, fiberErr := newPendingUpload(c, user.Id, data, maxSize)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+	upload.ChunkSize = chunkSize
+	if chunkCount(upload) > maxChunks {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(ErrorResponse{Message: "File is too large"})
+	}
+
+	multipartId, err := storage.StartMultipartUpload(c.Context(), upload.Filename, upload.MimeType)
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+	upload.MultipartId = &multipartId
+
+	if err = repository.DB.Create(&upload).Error; err != nil {
+		_ = storage.AbortMultipartUpload(c.Context(), upload.Filename, multipartId)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving upload"})
+	}
+
+	return c.JSON(DataResponse[ChunkedUploadResponse]{
+		Data: newChunkedUploadResponse(upload, nil),
+	})
+}
+
+// UploadChunk принимает часть файла
+// @Summary      Загрузка части файла
+// @Description  Принимает часть с номером chunk (с 1) в теле запроса. Размер части — chunkSize, последней — остаток файла. Повторно отправленная часть заменяет прежнюю. Если передан заголовок Content-MD5, хранилище сверяет с ним содержимое части
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Accept       application/octet-stream
+// @Produce      json
+// @Param        id     path      int  true  "Id загрузки"
+// @Param        chunk  path      int  true  "Номер части, начиная с 1"
+// @Success      200    {object}  MessageResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      410    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /api/uploadChunk/{id}/{chunk} [put]
+func UploadChunk(c *fiber.Ctx) error {
+	upload, fiberErr := findChunkedUpload(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+
+	chunk, err := strconv.Atoi(c.Params("chunk"))
+	if err != nil || chunk < 1 || chunk > chunkCount(upload) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid chunk number"})
+	}
+
+	body := c.Body()
+	if int64(len(body)) != chunkLength(upload, chunk) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "Invalid chunk size"})
+	}
+
+	err = storage.UploadPart(c.Context(), upload.Filename, *upload.MultipartId, chunk, bytes.NewReader(body), int64(len(body)), c.Get("Content-MD5"))
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	return c.JSON(MessageResponse{Message: "Chunk uploaded"})
+}
+
+// GetChunkedUpload возвращает состояние загрузки частями
+// @Summary      Состояние загрузки частями
+// @Description  Возвращает номера полученных частей и offset — число байт, полученных подряд с начала файла. После обрыва связи загрузку продолжают с первой недостающей части
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "Id загрузки"
+// @Success      200  {object}  DataResponse[ChunkedUploadResponse]
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      410  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/getChunkedUpload/{id} [get]
+func GetChunkedUpload(c *fiber.Ctx) error {
+	upload, fiberErr := findChunkedUpload(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+
+	parts, err := storage.ListUploadedParts(c.Context(), upload.Filename, *upload.MultipartId)
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	return c.JSON(DataResponse[ChunkedUploadResponse]{
+		Data: newChunkedUploadResponse(upload, parts),
+	})
+}
+
+// CompleteChunkedUpload собирает файл из частей
+// @Summary      Завершение загрузки частями
+// @Description  Собирает файл из всех частей и проверяет его так же, как /api/completeUpload. Если получены не все части, возвращается 409 и загрузку можно продолжить
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "Id загрузки"
+// @Success      200  {object}  DataResponse[FileResponse]
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      410  {object}  ErrorResponse
+// @Failure      413  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/completeChunkedUpload/{id} [post]
+func CompleteChunkedUpload(c *fiber.Ctx) error {
+	upload, fiberErr := findChunkedUpload(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+
+	// Объект уже собран, если предыдущая попытка завершения не дошла до создания записи файла
+	if info, err := storage.StatFromMinIO(c.Context(), upload.Filename); err == nil {
+		return finishUpload(c, upload, info.Size)
+	}
+
+	parts, err := storage.ListUploadedParts(c.Context(), upload.Filename, *upload.MultipartId)
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	var size int64
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.Size != chunkLength(upload, part.PartNumber) {
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Message: "Not all chunks have been uploaded"})
+		}
+		size += part.Size
+	}
+	if len(parts) != chunkCount(upload) {
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Message: "Not all chunks have been uploaded"})
+	}
+
+	if err = storage.CompleteMultipartUpload(c.Context(), upload.Filename, *upload.MultipartId, parts); err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+
+	return finishUpload(c, upload, size)
+}
+
+// AbortChunkedUpload отменяет загрузку частями
+// @Summary      Отмена загрузки частями
+// @Description  Отменяет загрузку и удаляет полученные части
+// @Tags         File
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "Id загрузки"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      410  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/abortChunkedUpload/{id} [delete]
+func AbortChunkedUpload(c *fiber.Ctx) error {
+	upload, fiberErr := findChunkedUpload(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Message: fiberErr.Message})
+	}
+
+	if err := discardUpload(c.Context(), upload); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error aborting upload"})
+	}
+
+	return c.JSON(MessageResponse{Message: "Upload aborted"})
+}
+
+// newPendingUpload проверяет заявленный файл и готовит запись загрузки с уникальным именем объекта.
+// Заодно удаляет просроченные загрузки пользователя.
+func newPendingUpload(c *fiber.Ctx, userId uint, data RequestUploadRequest, maxSize int64) (models.PendingUpload, *fiber.Error) {
+	if data.Size <= 0 {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusBadRequest, "File size is required")
+	}
+	if data.Size > maxSize {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusRequestEntityTooLarge, "File is too large")
+	}
+
+	mediaType, _, err := mime.ParseMediaType(data.MimeType)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusBadRequest, "Invalid MIME type")
+	}
+	category := strings.Split(mediaType, "/")[0]
+	if data.Type != "" && data.Type != category {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusBadRequest, "File type is not allowed")
+	}
+
+	visibility, ok := parseVisibility(data.Visibility)
+	if !ok {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusBadRequest, "Invalid visibility")
+	}
+
+	discardExpiredUploads(c.Context(), userId)
+
+	var pending int64
+	repository.DB.Model(&models.PendingUpload{}).Where("owner_id = ?", userId).Count(&pending)
+	if pending >= maxPendingUploads {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusTooManyRequests, "Too many unfinished uploads")
+	}
+
+	return models.PendingUpload{
+		OwnerId:    userId,
+		Filename:   objectPrefix(visibility) + category + "/" + GenerateUniqueFilename(data.Filename),
+		Size:       data.Size,
+		MimeType:   data.MimeType,
+		Type:       data.Type,
+		Visibility: visibility,
+		ExpiresAt:  time.Now().Add(pendingUploadTTL),
+	}, nil
+}
+
+// findPendingUpload находит незавершенную загрузку текущего пользователя по id из пути.
+// Просроченная загрузка удаляется.
+func findPendingUpload(c *fiber.Ctx) (models.PendingUpload, *fiber.Error) {
+	user := CurrentUser(c)
+
+	uploadId, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil || uploadId == 0 {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusBadRequest, "Invalid upload id")
+	}
+
+	var upload models.PendingUpload
+	if err = repository.DB.Where("id = ? AND owner_id = ?", uploadId, user.Id).First(&upload).Error; err != nil {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusNotFound, "Upload not found")
+	}
+
+	if upload.ExpiresAt.Before(time.Now()) {
+		_ = discardUpload(c.Context(), upload)
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusGone, "Upload has expired")
+	}
+
+	return upload, nil
+}
+
+// finishUpload проверяет загруженный объект и создает запись файла вместо записи загрузки
+func finishUpload(c *fiber.Ctx, upload models.PendingUpload, size int64) error {
+	if size != upload.Size {
+		_ = discardUpload(c.Context(), upload)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "File size does not match"})
+	}
+
+	// Тип определяем по содержимому, как при загрузке через API, а не по заявленному Content-Type
+	head, err := storage.ReadFromMinIO(c.Context(), upload.Filename, 512)
+	if err != nil {
+		return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+	}
+	mimeType := http.DetectContentType(head)
+
+	if upload.Type != "" && upload.Type != strings.Split(mimeType, "/")[0] {
+		_ = discardUpload(c.Context(), upload)
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Message: "File type is not allowed"})
+	}
+
+	file := models.File{
+		Filename: upload.Filename,
+		MimeType: mimeType,
+		Variants: map[string]string{},
+	}
+
+	if media.Supported(mimeType) {
+		if size > media.MaxFileSize {
+			_ = discardUpload(c.Context(), upload)
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(ErrorResponse{Message: "Image is too large to process"})
+		}
+
+		data, err := storage.ReadFromMinIO(c.Context(), upload.Filename, size)
+		if err != nil {
+			return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+		}
+
+		file, err = storeImage(c.Context(), data, upload.Filename, mimeType, media.PresetPreview)
+		if err != nil {
+			if code := err.(*fiber.Error).Code; code == fiber.StatusBadRequest || code == fiber.StatusRequestEntityTooLarge {
+				_ = discardUpload(c.Context(), upload)
+			}
+			return c.Status(err.(*fiber.Error).Code).JSON(ErrorResponse{Message: err.(*fiber.Error).Error()})
+		}
+	}
+
+	file.OwnerId = upload.OwnerId
+	file.Visibility = upload.Visibility
+
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&upload).Error; err != nil {
+			return err
+		}
+		return tx.Create(&file).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Message: "Error saving file to DB"})
+	}
+
+	// Изображение сохранено в другом формате под новым именем, загруженный объект больше не нужен
+	if file.Filename != upload.Filename {
+		_ = storage.DeleteFromMinIO(c.Context(), upload.Filename)
+	}
+
+	return c.JSON(DataResponse[FileResponse]{
+		Data:    newFileResponse(file),
+		Message: "Файл загружен",
+	})
+}
+
+// findChunkedUpload находит незавершенную загрузку частями текущего пользователя по id из пути
+func findChunkedUpload(c *fiber.Ctx) (models.PendingUpload, *fiber.Error) {
+	upload, err := findPendingUpload(c)
+	if err != nil {
+		return upload, err
+	}
+	if upload.MultipartId == nil {
+		return models.PendingUpload{}, fiber.NewError(fiber.StatusBadRequest, "Upload is not chunked")
+	}
+	return upload, nil
+}
+
+// chunkCount возвращает число частей файла
+func chunkCount(upload models.PendingUpload) int {
+	return int((upload.Size + upload.ChunkSize - 1) / upload.ChunkSize)
+}
+
+// chunkLength возвращает размер части с номером chunk: все части, кроме последней, размером ChunkSize
+func chunkLength(upload models.PendingUpload, chunk int) int64 {
+	if chunk == chunkCount(upload) {
+		return upload.Size - int64(chunk-1)*upload.ChunkSize
+	}
+	return upload.ChunkSize
+}
+
+// newChunkedUploadResponse собирает состояние загрузки по списку полученных частей
+func newChunkedUploadResponse(upload models.PendingUpload, parts []minio.ObjectPart) ChunkedUploadResponse {
+	received := make([]int, 0, len(parts))
+	var offset int64
+	contiguous := true
+	for i, part := range parts {
+		received = append(received, part.PartNumber)
+		if contiguous && part.PartNumber == i+1 {
+			offset += part.Size
+		} else {
+			contiguous = false
+		}
+	}
+
+	return ChunkedUploadResponse{
+		UploadId:       upload.Id,
+		Size:           upload.Size,
+		ChunkSize:      upload.ChunkSize,
+		Chunks:         chunkCount(upload),
+		ReceivedChunks: received,
+		Offset:         offset,
+		ExpiresAt:      upload.ExpiresAt,
+	}
+}
+
+// discardUpload удаляет загрузку, ее части и объект в хранилище, если он успел появиться. Если составную
+// загрузку не удалось отменить, запись остается: иначе части так и заняли бы место в хранилище,
+// а фоновая очистка повторит попытку.
+func discardUpload(ctx context.Context, upload models.PendingUpload) error {
+	if upload.MultipartId != nil {
+		if err := storage.AbortMultipartUpload(ctx, upload.Filename, *upload.MultipartId); err != nil {
+			return err
+		}
+	}
+	_ = storage.DeleteFromMinIO(ctx, upload.Filename)
+	return repository.DB.Delete(&upload).Error
+}
+
+// discardExpiredUploads удаляет неподтвержденные в срок загрузки пользователя
+func discardExpiredUploads(ctx context.Context, userId uint) {
+	var expired []models.PendingUpload
+	repository.DB.Where("owner_id = ? AND expires_at < ?", userId, time.Now()).Find(&expired)
+	for _, upload := range expired {
+		_ = discardUpload(ctx, upload)
+	}
+}
+
+// DiscardExpiredUploads удаляет неподтвержденные в срок загрузки всех пользователей, включая загрузки частями,
+// и возвращает число удаленных. Вызывается фоновой задачей очистки: брошенные загрузки не должны оставлять
+// в хранилище ни объекты, ни части незавершенных составных загрузок.
+func DiscardExpiredUploads(ctx context.Context) (int, error) {
+	var expired []models.PendingUpload
+	if err := repository.DB.Where("expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+
+	discarded := 0
+	for _, upload := range expired {
+		if err := discardUpload(ctx, upload); err != nil {
+			log.Printf("Не удалось удалить загрузку %d: %v", upload.Id, err)
+			continue
+		}
+		discarded++
+	}
+	return discarded, nil
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/repository"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+const (
+	// maxCodeAttempts — после стольких неверных вводов код подтверждения аннулируется
+	maxCodeAttempts = 5
+
+	// loginLockThreshold — с этой неудачной попытки входа подряд аккаунт временно блокируется.
+	// Блокировка длится loginLockBase и удваивается с каждой следующей ошибкой, но не дольше loginLockMax.
+	loginLockThreshold = 5
+	loginLockBase      = time.Minute
+	loginLockMax       = time.Hour
+)
+
+var (
+	errCodeInvalid          = errors.New("invalid or expired code")
+	errCodeAttemptsExceeded = errors.New("too many code attempts")
+)
+
+// generateCode создает код подтверждения из 6 символов
+func generateCode() (string, error) {
+	return randomCode(6)
+}
+
+// randomCode создает строку из length символов charset криптографически стойким генератором
+func randomCode(length int) (string, error) {
+	code := make([]byte, length)
+	limit := big.NewInt(int64(len(charset)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		code[i] = charset[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// checkVerificationCode сверяет код пользователя. Каждый неверный ввод увеличивает счетчик попыток,
+// после maxCodeAttempts код удаляется и нужно запрашивать новый.
+func checkVerificationCode(userId uint, codeType string, code string) (models.VerificationCode, error) {
+	var verification models.VerificationCode
+	if err := repository.DB.Where("user_id = ? AND type = ? AND expires_at > ?", userId, codeType, time.Now()).
+		First(&verification).Error; err != nil {
+		return verification, errCodeInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(verification.Code), []byte(code)) == 1 {
+		return verification, nil
+	}
+
+	if verification.Attempts+1 >= maxCodeAttempts {
+		repository.DB.Delete(&verification)
+		return verification, errCodeAttemptsExceeded
+	}
+
+	repository.DB.Model(&verification).UpdateColumn("attempts", verification.Attempts+1)
+	return verification, errCodeInvalid
+}
+
+// verificationCodeError отвечает на неверный код подтверждения
+func verificationCodeError(c *fiber.Ctx, err error) error {
+	message := "Invalid or expired code"
+	if errors.Is(err, errCodeAttemptsExceeded) {
+		message = "Too many invalid attempts, request a new code"
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+		Message: message,
+	})
+}
+
+// loginLockDuration возвращает блокировку после failed неудачных попыток входа подряд
+func loginLockDuration(failed int) time.Duration {
+	if failed < loginLockThreshold {
+		return 0
+	}
+
+	lock := loginLockBase
+	for i := loginLockThreshold; i < failed && lock < loginLockMax; i++ {
+		lock *= 2
+	}
+	return min(lock, loginLockMax)
+}
+
+// registerFailedLogin учитывает неудачную попытку входа и при необходимости блокирует аккаунт
+func registerFailedLogin(user *models.User) (time.Duration, error) {
+	var failed int
+	if err := repository.DB.Raw(`UPDATE users SET failed_logins = failed_logins + 1
+		WHERE id = ? RETURNING failed_logins`, user.Id).Scan(&failed).Error; err != nil {
+		return 0, err
+	}
+
+	lock := loginLockDuration(failed)
+	if lock > 0 {
+		if err := repository.DB.Model(user).UpdateColumn("locked_until", time.Now().Add(lock)).Error; err != nil {
+			return 0, err
+		}
+	}
+	return lock, nil
+}
+
+// resetFailedLogins снимает блокировку после успешного входа
+func resetFailedLogins(user *models.User) {
+	if user.FailedLogins == 0 && user.LockedUntil == nil {
+		return
+	}
+
+	repository.DB.Model(user).UpdateColumns(map[string]interface{}{
+		"failed_logins": 0,
+		"locked_until":  nil,
+	})
+}
+
+// accountLocked отвечает 429 на вход в заблокированный аккаунт
+func accountLocked(c *fiber.Ctx, lock time.Duration) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(int(lock.Seconds()+0.5), 1)))
+	return c.Status(fiber.StatusTooManyRequests).JSON(ErrorResponse{
+		Message: "Too many failed login attempts, account is temporarily locked",
+	})
+}
@@ -0,0 +1,161 @@
+package mail
+
+import (
+	"blogpoint-backend/internal/models"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+const (
+	// outboxBatch — сколько писем воркер забирает за один проход
+	outboxBatch = 20
+	// outboxPollInterval — как часто воркер проверяет очередь, если его не разбудили раньше
+	outboxPollInterval = 30 * time.Second
+	// outboxClaimTimeout — на это время письмо скрывается от других воркеров, пока отправляется.
+	// Если экземпляр упадет во время отправки, письмо снова станет доступным после таймаута.
+	outboxClaimTimeout = 5 * time.Minute
+	// outboxMaxAttempts — после стольких неудачных попыток письмо помечается failed
+	outboxMaxAttempts = 8
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+// Outbox сохраняет письма в таблицу outbox_emails и отправляет их в фоне через транспорт,
+// повторяя неудачные попытки с растущей задержкой
+type Outbox struct {
+	db        *gorm.DB
+	transport Transport
+	wake      chan struct{}
+}
+
+func NewOutbox(db *gorm.DB, transport Transport) *Outbox {
+	return &Outbox{
+		db:        db,
+		transport: transport,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// SendMessage ставит письмо в очередь. Ошибка возвращается, только если письмо не удалось сохранить.
+func (o *Outbox) SendMessage(message Message, to []string) error {
+	entry := models.OutboxEmail{
+		Recipients:    to,
+		Subject:       message.Subject,
+		Html:          message.HTML,
+		Text:          message.Text,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := o.db.Create(&entry).Error; err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start запускает воркер отправки
+func (o *Outbox) Start() {
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			o.process()
+
+			select {
+			case <-o.wake:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// process отправляет все письма, время которых наступило
+func (o *Outbox) process() {
+	for {
+		entries, err := o.claim()
+		if err != nil {
+			log.Printf("Не удалось получить письма из очереди: %v", err)
+			return
+		}
+
+		for _, entry := range entries {
+			o.deliver(entry)
+		}
+
+		if len(entries) < outboxBatch {
+			return
+		}
+	}
+}
+
+// claim забирает пачку писем и откладывает их следующую попытку на outboxClaimTimeout,
+// чтобы параллельные воркеры не отправили одно письмо дважды
+func (o *Outbox) claim() ([]models.OutboxEmail, error) {
+	var entries []models.OutboxEmail
+	err := o.db.Raw(`UPDATE outbox_emails SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_emails
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		time.Now().Add(outboxClaimTimeout), models.OutboxStatusPending, time.Now(), outboxBatch).
+		Scan(&entries).Error
+	return entries, err
+}
+
+func (o *Outbox) deliver(entry models.OutboxEmail) {
+	message := Message{
+		Subject: entry.Subject,
+		HTML:    entry.Html,
+		Text:    entry.Text,
+	}
+
+	sendErr := o.transport.Deliver(message, entry.Recipients)
+	now := time.Now()
+
+	var updates map[string]interface{}
+	switch {
+	case sendErr == nil:
+		updates = map[string]interface{}{
+			"status":     models.OutboxStatusSent,
+			"attempts":   entry.Attempts + 1,
+			"sent_at":    now,
+			"last_error": "",
+		}
+	case entry.Attempts+1 >= outboxMaxAttempts:
+		log.Printf("Письмо %d не отправлено после %d попыток: %v", entry.Id, entry.Attempts+1, sendErr)
+		updates = map[string]interface{}{
+			"status":     models.OutboxStatusFailed,
+			"attempts":   entry.Attempts + 1,
+			"last_error": sendErr.Error(),
+		}
+	default:
+		updates = map[string]interface{}{
+			"attempts":        entry.Attempts + 1,
+			"next_attempt_at": now.Add(outboxBackoff(entry.Attempts + 1)),
+			"last_error":      sendErr.Error(),
+		}
+	}
+
+	if err := o.db.Model(&models.OutboxEmail{}).Where("id = ?", entry.Id).Updates(updates).Error; err != nil {
+		log.Printf("Не удалось обновить состояние письма %d: %v", entry.Id, err)
+	}
+}
+
+// outboxBackoff — задержка перед следующей попыткой: 30 секунд, минута, две и так далее, но не больше часа
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxBackoff)
+}
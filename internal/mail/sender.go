@@ -1,64 +1,8 @@
 package mail
 
-import (
-	"fmt"
-	"github.com/jordan-wright/email"
-	"net/smtp"
-)
-
-const (
-	smtpAuthAddress   = "smtp.gmail.com"
-	smtpServerAddress = "smtp.gmail.com:587"
-)
-
+// EmailSender отправляет письма пользователям. Обработчики запросов получают реализацию,
+// которая только ставит письмо в очередь, поэтому отправка не задерживает ответ.
 type EmailSender interface {
-	SendEmail(
-		subject string,
-		content string,
-		to []string,
-		cc []string,
-		bcc []string,
-		attachFiles []string,
-	) error
-}
-
-type GmailSender struct {
-	name              string
-	fromEmailAddress  string
-	fromEmailPassword string
-}
-
-func NewGmailSender(name string, fromEmailAddress string, fromEmailPassword string) EmailSender {
-	return &GmailSender{
-		name:              name,
-		fromEmailAddress:  fromEmailAddress,
-		fromEmailPassword: fromEmailPassword,
-	}
-}
-
-func (sender *GmailSender) SendEmail(
-	subject string,
-	content string,
-	to []string,
-	cc []string,
-	bcc []string,
-	attachFiles []string,
-) error {
-	e := email.NewEmail()
-	e.From = fmt.Sprintf("%s <%s>", sender.name, sender.fromEmailAddress)
-	e.Subject = subject
-	e.HTML = []byte(content)
-	e.To = to
-	e.Cc = cc
-	e.Bcc = bcc
-
-	for _, f := range attachFiles {
-		_, err := e.AttachFile(f)
-		if err != nil {
-			return fmt.Errorf("failed to attach file %s: %w", f, err)
-		}
-	}
-
-	smtpAuth := smtp.PlainAuth("", sender.fromEmailAddress, sender.fromEmailPassword, smtpAuthAddress)
-	return e.Send(smtpServerAddress, smtpAuth)
+	// SendMessage отправляет письмо, собранное из шаблона, с HTML и текстовой частью
+	SendMessage(message Message, to []string) error
 }
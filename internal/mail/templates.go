@@ -0,0 +1,180 @@
+package mail
+
+import (
+	"blogpoint-backend/internal/config"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// BrandName — название в оформлении писем
+const BrandName = "Blog Point"
+
+// siteURL — адрес фронтенда для ссылок в письмах, задается через Configure
+var siteURL string
+
+// Configure задает адрес фронтенда, на который ведут ссылки в письмах
+func Configure(cfg config.MailConfig) {
+	siteURL = strings.TrimSuffix(cfg.SiteURL, "/")
+}
+
+// SiteURL возвращает адрес фронтенда без завершающего слеша
+func SiteURL() string {
+	return siteURL
+}
+
+// Языки писем. Для неизвестного языка используется DefaultLanguage.
+const (
+	LanguageRu      = "ru"
+	LanguageEn      = "en"
+	DefaultLanguage = LanguageRu
+)
+
+// Типы писем
+const (
+	TemplateEmailVerification = "email_verification"
+	TemplatePasswordReset     = "password_reset"
+	TemplateAccountDeletion   = "account_deletion"
+	TemplateComplaintResolved = "complaint_resolved"
+)
+
+// CodeData — данные писем с кодом подтверждения
+type CodeData struct {
+	Code         string
+	ValidMinutes int
+}
+
+// LinkData — данные писем со ссылкой
+type LinkData struct {
+	Link         string
+	ValidMinutes int
+}
+
+// ComplaintResolvedData — данные письма о рассмотренной жалобе
+type ComplaintResolvedData struct {
+	TargetType    string
+	ComplaintType string
+	Resolution    string
+}
+
+// Message — готовое письмо: тема, HTML и текстовая версия для клиентов без HTML
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Шаблоны лежат в templates/<язык>/<тип>.html и .txt. HTML-часть определяет блок content,
+// текстовая — блоки subject и content. Общая обертка — templates/layout.html и layout.txt,
+// подпись — блок footer в <язык>/footer.html и .txt.
+//
+//go:embed templates
+var templateFiles embed.FS
+
+type messageTemplates struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// templates[язык][тип] разбираются при запуске, чтобы ошибка в шаблоне не дожидалась первой отправки
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]map[string]messageTemplates {
+	parsed := make(map[string]map[string]messageTemplates)
+
+	paths, err := fs.Glob(templateFiles, "templates/*/*.html")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, htmlPath := range paths {
+		language := path.Base(path.Dir(htmlPath))
+		name := strings.TrimSuffix(path.Base(htmlPath), ".html")
+		if name == "footer" {
+			continue
+		}
+
+		funcs := map[string]interface{}{
+			"brandName": func() string { return BrandName },
+			"siteUrl":   SiteURL,
+			"lang":      func() string { return language },
+		}
+		dir := path.Dir(htmlPath)
+
+		html, err := htmltemplate.New(name).Funcs(funcs).
+			ParseFS(templateFiles, "templates/layout.html", dir+"/footer.html", htmlPath)
+		if err != nil {
+			panic(fmt.Sprintf("mail template %s: %v", htmlPath, err))
+		}
+
+		textPath := strings.TrimSuffix(htmlPath, ".html") + ".txt"
+		text, err := texttemplate.New(name).Funcs(funcs).
+			ParseFS(templateFiles, "templates/layout.txt", dir+"/footer.txt", textPath)
+		if err != nil {
+			panic(fmt.Sprintf("mail template %s: %v", textPath, err))
+		}
+
+		if parsed[language] == nil {
+			parsed[language] = make(map[string]messageTemplates)
+		}
+		parsed[language][name] = messageTemplates{html: html, text: text}
+	}
+
+	return parsed
+}
+
+// Render собирает письмо типа name на языке пользователя
+func Render(name string, language string, data interface{}) (Message, error) {
+	set, ok := templates[language][name]
+	if !ok {
+		set, ok = templates[DefaultLanguage][name]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("unknown mail template %q", name)
+	}
+
+	var subject, text bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("render subject of %s: %w", name, err)
+	}
+	if err := set.text.ExecuteTemplate(&text, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("render text of %s: %w", name, err)
+	}
+
+	var html bytes.Buffer
+	if err := set.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("render html of %s: %w", name, err)
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+// TemplateNames возвращает типы писем, для которых есть шаблоны
+func TemplateNames() []string {
+	var names []string
+	for name := range templates[DefaultLanguage] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Languages возвращает языки, на которых есть шаблоны
+func Languages() []string {
+	var languages []string
+	for language := range templates {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
@@ -0,0 +1,140 @@
+package mail
+
+import (
+	"blogpoint-backend/internal/config"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"github.com/jordan-wright/email"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport доставляет готовое письмо получателям
+type Transport interface {
+	Deliver(message Message, to []string) error
+}
+
+// NewTransport создает транспорт, выбранный в конфигурации
+func NewTransport(cfg config.MailConfig) (Transport, error) {
+	from := fmt.Sprintf("%s <%s>", cfg.SenderName, cfg.SenderAddress)
+
+	switch cfg.Transport {
+	case config.MailTransportSMTP:
+		return NewSMTPTransport(from, cfg.SMTP, cfg.SenderAddress, cfg.SenderPassword), nil
+	case config.MailTransportFile:
+		return NewFileTransport(from, cfg.FileDir)
+	case config.MailTransportNoop:
+		return NoopTransport{}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", cfg.Transport)
+	}
+}
+
+func newEmail(from string, message Message, to []string) *email.Email {
+	e := email.NewEmail()
+	e.From = from
+	e.To = to
+	e.Subject = message.Subject
+	e.HTML = []byte(message.HTML)
+	e.Text = []byte(message.Text)
+	return e
+}
+
+// SMTPTransport отправляет письма через SMTP-сервер
+type SMTPTransport struct {
+	from     string
+	address  string
+	host     string
+	tlsMode  string
+	username string
+	password string
+}
+
+func NewSMTPTransport(from string, cfg config.SMTPConfig, senderAddress string, password string) *SMTPTransport {
+	username := cfg.Username
+	if username == "" {
+		username = senderAddress
+	}
+
+	return &SMTPTransport{
+		from:     from,
+		address:  net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		host:     cfg.Host,
+		tlsMode:  cfg.TLS,
+		username: username,
+		password: password,
+	}
+}
+
+func (t *SMTPTransport) Deliver(message Message, to []string) error {
+	e := newEmail(t.from, message, to)
+
+	var auth smtp.Auth
+	if t.password != "" {
+		auth = smtp.PlainAuth("", t.username, t.password, t.host)
+	}
+
+	tlsConfig := &tls.Config{ServerName: t.host}
+	switch t.tlsMode {
+	case config.SMTPTLSImplicit:
+		return e.SendWithTLS(t.address, auth, tlsConfig)
+	case config.SMTPTLSStartTLS:
+		return e.SendWithStartTLS(t.address, auth, tlsConfig)
+	default:
+		// STARTTLS используется, если сервер его предлагает
+		return e.Send(t.address, auth)
+	}
+}
+
+// FileTransport сохраняет письма в каталог в формате maildir (new/*.eml). Нужен для разработки
+// и тестов: письма можно открыть почтовым клиентом, ничего не отправляя.
+type FileTransport struct {
+	from string
+	dir  string
+}
+
+func NewFileTransport(from string, dir string) (*FileTransport, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create mail directory: %w", err)
+		}
+	}
+
+	return &FileTransport{from: from, dir: dir}, nil
+}
+
+func (t *FileTransport) Deliver(message Message, to []string) error {
+	content, err := newEmail(t.from, message, to).Bytes()
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 6)
+	if _, err = rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d.%s.eml", time.Now().UnixNano(), hex.EncodeToString(suffix))
+
+	// Как принято в maildir, файл пишется в tmp и переносится в new целиком
+	tmpPath := filepath.Join(t.dir, "tmp", name)
+	if err = os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(t.dir, "new", name))
+}
+
+// NoopTransport ничего не отправляет, а только пишет в лог тему и получателей
+type NoopTransport struct{}
+
+func (NoopTransport) Deliver(message Message, to []string) error {
+	log.Printf("Письмо «%s» для %s не отправлено: почта отключена", message.Subject, strings.Join(to, ", "))
+	return nil
+}
@@ -0,0 +1,164 @@
+package media
+
+import (
+	"blogpoint-backend/internal/config"
+	"bytes"
+	"errors"
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
+	"image"
+)
+
+// Preset — набор вариантов, которые создаются для изображения
+type Preset string
+
+const (
+	PresetNone    Preset = ""
+	PresetAvatar  Preset = "avatar"
+	PresetPreview Preset = "preview"
+)
+
+const (
+	// MaxFileSize — изображения больше этого размера не обрабатываются и не принимаются
+	MaxFileSize = 20 << 20
+	// maxPixels защищает от изображений, которые при декодировании займут слишком много памяти
+	maxPixels = 40_000_000
+
+	jpegQuality = 85
+)
+
+var (
+	// ErrTooLarge — изображение слишком большое для обработки
+	ErrTooLarge = errors.New("image is too large to process")
+	// ErrInvalidImage — файл не удалось декодировать как изображение
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+// Variant — уменьшенная копия изображения. Если Crop, изображение обрезается по центру до Width x Height,
+// иначе уменьшается до ширины Width с сохранением пропорций (и не увеличивается).
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+var presets = map[Preset][]Variant{
+	PresetAvatar: {
+		{Name: "avatar_64", Width: 64, Height: 64, Crop: true},
+		{Name: "avatar_128", Width: 128, Height: 128, Crop: true},
+		{Name: "avatar_256", Width: 256, Height: 256, Crop: true},
+	},
+	PresetPreview: {
+		{Name: "preview_640", Width: 640},
+		{Name: "preview_1280", Width: 1280},
+	},
+}
+
+// Output — закодированное изображение, готовое к загрузке в хранилище
+type Output struct {
+	Name     string
+	Data     []byte
+	MimeType string
+	Ext      string
+}
+
+// Result — результат обработки. Original — исходное изображение без метаданных (EXIF и XMP удаляются
+// при перекодировании, ориентация из EXIF применяется заранее). Формат Original может отличаться от исходного,
+// см. encode.
+type Result struct {
+	Original *Output
+	Variants []Output
+}
+
+// useWebP — сохранять PNG и WebP в WebP, см. config.MediaConfig
+var useWebP bool
+
+// Configure применяет настройки обработки изображений
+func Configure(cfg config.MediaConfig) {
+	useWebP = cfg.WebP
+}
+
+// Supported сообщает, обрабатываются ли изображения этого типа. GIF не обрабатывается, чтобы не потерять анимацию.
+func Supported(mimeType string) bool {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Process перекодирует изображение без метаданных и создает варианты из набора preset
+func Process(data []byte, mimeType string, preset Preset) (*Result, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	original, err := encode(img, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	result := Result{Original: original}
+
+	bounds := img.Bounds()
+	for _, variant := range presets[preset] {
+		var resized image.Image
+		switch {
+		case variant.Crop:
+			resized = imaging.Fill(img, variant.Width, variant.Height, imaging.Center, imaging.Lanczos)
+		case bounds.Dx() > variant.Width:
+			resized = imaging.Resize(img, variant.Width, 0, imaging.Lanczos)
+		default:
+			// Меньшие изображения не увеличиваем, клиент использует оригинал
+			continue
+		}
+
+		output, err := encode(resized, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		output.Name = variant.Name
+		result.Variants = append(result.Variants, *output)
+	}
+
+	return &result, nil
+}
+
+// encode кодирует изображение в формат источника: JPEG остается JPEG, PNG и WebP сохраняются в WebP без потерь,
+// если он включен в настройках, иначе в PNG. Изображения больше 16384 пикселей по стороне WebP не вмещает,
+// они тоже сохраняются в PNG.
+func encode(img image.Image, mimeType string) (*Output, error) {
+	var buffer bytes.Buffer
+	var output Output
+
+	bounds := img.Bounds()
+	switch {
+	case mimeType == "image/jpeg":
+		if err := imaging.Encode(&buffer, img, imaging.JPEG, imaging.JPEGQuality(jpegQuality)); err != nil {
+			return nil, err
+		}
+		output.MimeType, output.Ext = "image/jpeg", ".jpg"
+	case useWebP && bounds.Dx() <= webpMaxSize && bounds.Dy() <= webpMaxSize:
+		if err := encodeWebP(&buffer, img); err != nil {
+			return nil, err
+		}
+		output.MimeType, output.Ext = "image/webp", ".webp"
+	default:
+		if err := imaging.Encode(&buffer, img, imaging.PNG); err != nil {
+			return nil, err
+		}
+		output.MimeType, output.Ext = "image/png", ".png"
+	}
+
+	output.Data = buffer.Bytes()
+	return &output, nil
+}
@@ -0,0 +1,686 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// Кодировщик WebP без потерь (VP8L, RFC 9649). В golang.org/x/image есть только декодер, поэтому кодируем сами:
+// вычитание зеленого, предсказание по блокам, LZ77 и коды Хаффмана. Кэш цветов и остальные преобразования
+// не используются — они уменьшили бы файл еще немного ценой заметно более сложного кода.
+
+// webpMaxSize — наибольшая ширина и высота изображения в WebP
+const webpMaxSize = 1 << 14
+
+const (
+	vp8lSignature = 0x2f
+
+	transformPredictor     = 0
+	transformSubtractGreen = 2
+
+	// predictorBits — размер блока с общим режимом предсказания, 1<<predictorBits пикселей по стороне
+	predictorBits = 4
+
+	numLiteralCodes  = 256
+	numLengthCodes   = 24
+	numDistanceCodes = 40
+	maxHuffmanLength = 15
+	maxCodeLengthLen = 7
+
+	// Параметры поиска повторов LZ77: длина повтора ограничена форматом, расстояние — выбранным окном
+	minMatchLength = 3
+	maxMatchLength = 4096
+	matchWindow    = 1 << 18
+	maxChainLength = 32
+	hashBits       = 16
+	// Расстояния до 120 кодируются в формате особой таблицей соседей, обычные расстояния сдвинуты на 120
+	distanceCodeOffset = 120
+)
+
+var errWebPTooLarge = errors.New("image is too large for webp")
+
+// codeLengthCodeOrder — порядок, в котором записываются длины кодов для алфавита длин кодов
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// allPredictors — режимы предсказания, из которых выбирается лучший для каждого блока
+var allPredictors = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}
+
+// encodeWebP кодирует изображение в WebP без потерь
+func encodeWebP(w io.Writer, img image.Image) error {
+	return encodeWebPWith(w, img, allPredictors)
+}
+
+func encodeWebPWith(w io.Writer, img image.Image, predictors []int) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 || width > webpMaxSize || height > webpMaxSize {
+		return errWebPTooLarge
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+
+	argb := make([]uint32, width*height)
+	hasAlpha := false
+	for i := range argb {
+		p := nrgba.Pix[4*i : 4*i+4]
+		argb[i] = uint32(p[3])<<24 | uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+		if p[3] != 0xff {
+			hasAlpha = true
+		}
+	}
+
+	var bw bitWriter
+	bw.write(vp8lSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.write(boolBit(hasAlpha), 1)
+	bw.write(0, 3)
+
+	// Декодер отменяет преобразования в обратном порядке: сначала предсказание, затем вычитание зеленого
+	subtractGreen(argb)
+	bw.write(1, 1)
+	bw.write(transformSubtractGreen, 2)
+
+	modes, tilesX := choosePredictors(argb, width, height, predictors)
+	residuals := applyPredictors(argb, width, height, modes, tilesX)
+	bw.write(1, 1)
+	bw.write(transformPredictor, 2)
+	bw.write(predictorBits-2, 3)
+	modeImage := make([]uint32, len(modes))
+	for i, mode := range modes {
+		modeImage[i] = 0xff000000 | uint32(mode)<<8
+	}
+	writeImageData(&bw, literalTokens(modeImage), false)
+
+	bw.write(0, 1)
+	writeImageData(&bw, findMatches(residuals), true)
+
+	payload := bw.bytes()
+	padded := len(payload) + len(payload)&1
+
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(12+padded))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(payload)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	if padded != len(payload) {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+func boolBit(value bool) uint32 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// bitWriter пишет биты начиная с младшего, как того требует VP8L
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nBits uint
+}
+
+func (b *bitWriter) write(value uint32, n uint) {
+	b.acc |= uint64(value) << b.nBits
+	b.nBits += n
+	for b.nBits >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.nBits -= 8
+	}
+}
+
+func (b *bitWriter) bytes() []byte {
+	if b.nBits > 0 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc, b.nBits = 0, 0
+	}
+	return b.buf
+}
+
+func subtractGreen(argb []uint32) {
+	for i, p := range argb {
+		green := (p >> 8) & 0xff
+		redBlue := (p & 0x00ff00ff) + 0x01000100 - (green<<16 | green)
+		argb[i] = p&0xff00ff00 | redBlue&0x00ff00ff
+	}
+}
+
+// subPixels вычитает пиксели покомпонентно по модулю 256
+func subPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	redBlue := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+func average2(a, b uint32) uint32 {
+	return (((a ^ b) & 0xfefefefe) >> 1) + (a & b)
+}
+
+func channel(p uint32, shift uint) int32 {
+	return int32((p >> shift) & 0xff)
+}
+
+func clampChannel(v int32) uint32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint32(v)
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func selectPredictor(left, top, topLeft uint32) uint32 {
+	var predictLeft, predictTop int32
+	for shift := uint(0); shift < 32; shift += 8 {
+		predictLeft += abs32(channel(top, shift) - channel(topLeft, shift))
+		predictTop += abs32(channel(left, shift) - channel(topLeft, shift))
+	}
+	if predictLeft < predictTop {
+		return left
+	}
+	return top
+}
+
+func clampAddSubtractFull(a, b, c uint32) uint32 {
+	var result uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		result |= clampChannel(channel(a, shift)+channel(b, shift)-channel(c, shift)) << shift
+	}
+	return result
+}
+
+func clampAddSubtractHalf(a, b uint32) uint32 {
+	var result uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		ca := channel(a, shift)
+		result |= clampChannel(ca+(ca-channel(b, shift))/2) << shift
+	}
+	return result
+}
+
+// predict возвращает предсказание пикселя по соседям в одном из 14 режимов VP8L
+func predict(mode int, left, top, topRight, topLeft uint32) uint32 {
+	switch mode {
+	case 0:
+		return 0xff000000
+	case 1:
+		return left
+	case 2:
+		return top
+	case 3:
+		return topRight
+	case 4:
+		return topLeft
+	case 5:
+		return average2(average2(left, topRight), top)
+	case 6:
+		return average2(left, topLeft)
+	case 7:
+		return average2(left, top)
+	case 8:
+		return average2(topLeft, top)
+	case 9:
+		return average2(top, topRight)
+	case 10:
+		return average2(average2(left, topLeft), average2(top, topRight))
+	case 11:
+		return selectPredictor(left, top, topLeft)
+	case 12:
+		return clampAddSubtractFull(left, top, topLeft)
+	default:
+		return clampAddSubtractHalf(average2(left, top), topLeft)
+	}
+}
+
+// residual — разность пикселя и предсказания. Первая строка предсказывается левым соседом,
+// первый столбец — верхним, первый пиксель — непрозрачным черным, как в декодере.
+func residual(argb []uint32, width, x, y, mode int) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return subPixels(argb[i], 0xff000000)
+	case y == 0:
+		return subPixels(argb[i], argb[i-1])
+	case x == 0:
+		return subPixels(argb[i], argb[i-width])
+	}
+	// Для последнего столбца правым верхним соседом служит первый пиксель текущей строки,
+	// он же следующий за верхним в памяти
+	return subPixels(argb[i], predict(mode, argb[i-1], argb[i-width], argb[i-width+1], argb[i-width-1]))
+}
+
+// residualCost — грубая оценка того, сколько бит займет остаток: чем ближе компоненты к нулю, тем дешевле
+func residualCost(r uint32) int32 {
+	var cost int32
+	for shift := uint(0); shift < 32; shift += 8 {
+		cost += abs32(int32(int8(r >> shift)))
+	}
+	return cost
+}
+
+// choosePredictors выбирает для каждого блока режим предсказания с наименьшими остатками
+func choosePredictors(argb []uint32, width, height int, predictors []int) ([]uint8, int) {
+	size := 1 << predictorBits
+	tilesX := (width + size - 1) >> predictorBits
+	tilesY := (height + size - 1) >> predictorBits
+	modes := make([]uint8, tilesX*tilesY)
+
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			bestMode, bestCost := predictors[0], int32(-1)
+			for _, mode := range predictors {
+				var cost int32
+				for y := ty * size; y < min((ty+1)*size, height); y++ {
+					for x := tx * size; x < min((tx+1)*size, width); x++ {
+						cost += residualCost(residual(argb, width, x, y, mode))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					bestMode, bestCost = mode, cost
+				}
+			}
+			modes[ty*tilesX+tx] = uint8(bestMode)
+		}
+	}
+	return modes, tilesX
+}
+
+func applyPredictors(argb []uint32, width, height int, modes []uint8, tilesX int) []uint32 {
+	residuals := make([]uint32, len(argb))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			mode := int(modes[(y>>predictorBits)*tilesX+x>>predictorBits])
+			residuals[y*width+x] = residual(argb, width, x, y, mode)
+		}
+	}
+	return residuals
+}
+
+// token — пиксель как есть или повтор length пикселей, стоящих на distance пикселей раньше
+type token struct {
+	argb     uint32
+	length   int
+	distance int
+}
+
+func literalTokens(argb []uint32) []token {
+	tokens := make([]token, len(argb))
+	for i, p := range argb {
+		tokens[i] = token{argb: p}
+	}
+	return tokens
+}
+
+// findMatches жадно заменяет повторяющиеся участки ссылками назад (LZ77)
+func findMatches(argb []uint32) []token {
+	head := make([]int32, 1<<hashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, len(argb))
+	hash := func(i int) uint32 {
+		return (argb[i]*0x9e3779b1 ^ argb[i+1]*0x85ebca6b) >> (32 - hashBits)
+	}
+	insert := func(i int) {
+		if i+1 < len(argb) {
+			h := hash(i)
+			prev[i] = head[h]
+			head[h] = int32(i)
+		}
+	}
+
+	tokens := make([]token, 0, len(argb))
+	for i := 0; i < len(argb); {
+		bestLength, bestDistance := 0, 0
+		if i+minMatchLength <= len(argb) {
+			limit := min(maxMatchLength, len(argb)-i)
+			candidate := head[hash(i)]
+			for chain := 0; candidate >= 0 && chain < maxChainLength; chain++ {
+				distance := i - int(candidate)
+				if distance > matchWindow {
+					break
+				}
+				length := 0
+				for length < limit && argb[int(candidate)+length] == argb[i+length] {
+					length++
+				}
+				if length > bestLength {
+					bestLength, bestDistance = length, distance
+					if length == limit {
+						break
+					}
+				}
+				candidate = prev[candidate]
+			}
+		}
+
+		if bestLength >= minMatchLength {
+			tokens = append(tokens, token{length: bestLength, distance: bestDistance})
+			for j := i; j < i+bestLength; j++ {
+				insert(j)
+			}
+			i += bestLength
+			continue
+		}
+		tokens = append(tokens, token{argb: argb[i]})
+		insert(i)
+		i++
+	}
+	return tokens
+}
+
+// prefixCode разбивает длину или код расстояния (от 1) на префикс, записываемый кодом Хаффмана,
+// и дополнительные биты
+func prefixCode(value int) (prefix int, extraBits uint, extra uint32) {
+	d := value - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	high := bits.Len(uint(d)) - 1
+	second := (d >> (high - 1)) & 1
+	extraBits = uint(high - 1)
+	return 2*high + second, extraBits, uint32(d) & (1<<extraBits - 1)
+}
+
+// writeImageData записывает пиксели изображения: параметры кэша цветов, коды Хаффмана и сами данные.
+// Для основного изображения (topLevel) еще и признак мета-кодов; используется одна группа кодов на все изображение.
+func writeImageData(bw *bitWriter, tokens []token, topLevel bool) {
+	bw.write(0, 1) // без кэша цветов
+	if topLevel {
+		bw.write(0, 1) // без мета-кодов Хаффмана
+	}
+
+	green := make([]uint32, numLiteralCodes+numLengthCodes)
+	red := make([]uint32, numLiteralCodes)
+	blue := make([]uint32, numLiteralCodes)
+	alpha := make([]uint32, numLiteralCodes)
+	distance := make([]uint32, numDistanceCodes)
+	for _, t := range tokens {
+		if t.length == 0 {
+			green[(t.argb>>8)&0xff]++
+			red[(t.argb>>16)&0xff]++
+			blue[t.argb&0xff]++
+			alpha[t.argb>>24]++
+			continue
+		}
+		lengthPrefix, _, _ := prefixCode(t.length)
+		green[numLiteralCodes+lengthPrefix]++
+		distancePrefix, _, _ := prefixCode(t.distance + distanceCodeOffset)
+		distance[distancePrefix]++
+	}
+
+	codes := [5]huffmanCode{
+		writeHuffmanCode(bw, green),
+		writeHuffmanCode(bw, red),
+		writeHuffmanCode(bw, blue),
+		writeHuffmanCode(bw, alpha),
+		writeHuffmanCode(bw, distance),
+	}
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].write(bw, int((t.argb>>8)&0xff))
+			codes[1].write(bw, int((t.argb>>16)&0xff))
+			codes[2].write(bw, int(t.argb&0xff))
+			codes[3].write(bw, int(t.argb>>24))
+			continue
+		}
+		prefix, extraBits, extra := prefixCode(t.length)
+		codes[0].write(bw, numLiteralCodes+prefix)
+		bw.write(extra, extraBits)
+		prefix, extraBits, extra = prefixCode(t.distance + distanceCodeOffset)
+		codes[4].write(bw, prefix)
+		bw.write(extra, extraBits)
+	}
+}
+
+// huffmanCode — канонический код Хаффмана. Коды хранятся с обратным порядком бит, потому что поток
+// пишется с младшего бита, а код читается со старшего. Единственный символ алфавита кодируется нулем бит.
+type huffmanCode struct {
+	codes   []uint32
+	lengths []uint8
+}
+
+func (h huffmanCode) write(bw *bitWriter, symbol int) {
+	bw.write(h.codes[symbol], uint(h.lengths[symbol]))
+}
+
+// writeHuffmanCode строит код по частотам символов и записывает его описание
+func writeHuffmanCode(bw *bitWriter, counts []uint32) huffmanCode {
+	var used []int
+	for symbol, count := range counts {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+	if len(used) == 0 {
+		// Алфавит не используется, но описание кода все равно нужно
+		used = []int{0}
+	}
+
+	// Один-два символа до 256 описываются коротко: сами символы без длин кодов
+	if len(used) <= 2 && used[len(used)-1] < numLiteralCodes {
+		bw.write(1, 1)
+		bw.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(used[0]), 8)
+		}
+
+		code := huffmanCode{codes: make([]uint32, len(counts)), lengths: make([]uint8, len(counts))}
+		if len(used) == 2 {
+			bw.write(uint32(used[1]), 8)
+			code.codes[used[1]] = 1
+			code.lengths[used[0]], code.lengths[used[1]] = 1, 1
+		}
+		return code
+	}
+
+	lengths := huffmanLengths(counts, maxHuffmanLength)
+	tokens := codeLengthTokens(lengths)
+
+	tokenCounts := make([]uint32, len(codeLengthCodeOrder))
+	for _, t := range tokens {
+		tokenCounts[t.symbol]++
+	}
+	tokenLengths := huffmanLengths(tokenCounts, maxCodeLengthLen)
+	tokenCode := canonicalCode(tokenLengths)
+
+	numCodes := 4
+	for i, symbol := range codeLengthCodeOrder {
+		if tokenLengths[symbol] != 0 {
+			numCodes = max(numCodes, i+1)
+		}
+	}
+
+	bw.write(0, 1)
+	bw.write(uint32(numCodes-4), 4)
+	for _, symbol := range codeLengthCodeOrder[:numCodes] {
+		bw.write(uint32(tokenLengths[symbol]), 3)
+	}
+	bw.write(0, 1) // длины заданы для всего алфавита
+	for _, t := range tokens {
+		tokenCode.write(bw, t.symbol)
+		bw.write(t.extra, t.extraBits)
+	}
+
+	return canonicalCode(lengths)
+}
+
+// codeLengthToken — символ алфавита длин кодов: длина 0-15, 16 — повтор предыдущей ненулевой длины,
+// 17 и 18 — короткая и длинная серия нулей
+type codeLengthToken struct {
+	symbol    int
+	extra     uint32
+	extraBits uint
+}
+
+func codeLengthTokens(lengths []uint8) []codeLengthToken {
+	var tokens []codeLengthToken
+	for i := 0; i < len(lengths); {
+		value := lengths[i]
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == value {
+			run++
+		}
+		i += run
+
+		if value == 0 {
+			for run >= 11 {
+				n := min(run, 138)
+				tokens = append(tokens, codeLengthToken{symbol: 18, extra: uint32(n - 11), extraBits: 7})
+				run -= n
+			}
+			if run >= 3 {
+				tokens = append(tokens, codeLengthToken{symbol: 17, extra: uint32(run - 3), extraBits: 3})
+				run = 0
+			}
+			for ; run > 0; run-- {
+				tokens = append(tokens, codeLengthToken{symbol: 0})
+			}
+			continue
+		}
+
+		tokens = append(tokens, codeLengthToken{symbol: int(value)})
+		run--
+		for run >= 3 {
+			n := min(run, 6)
+			tokens = append(tokens, codeLengthToken{symbol: 16, extra: uint32(n - 3), extraBits: 2})
+			run -= n
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, codeLengthToken{symbol: int(value)})
+		}
+	}
+	return tokens
+}
+
+// huffmanLengths строит длины кодов Хаффмана не длиннее limit. Если дерево выходит глубже,
+// частоты уменьшаются вдвое, пока оно не уложится в ограничение.
+func huffmanLengths(counts []uint32, limit int) []uint8 {
+	lengths := make([]uint8, len(counts))
+	weights := make([]uint32, len(counts))
+	copy(weights, counts)
+
+	var symbols []int
+	for symbol, count := range weights {
+		if count > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	switch len(symbols) {
+	case 0:
+		return lengths
+	case 1:
+		lengths[symbols[0]] = 1
+		return lengths
+	}
+
+	for {
+		sort.SliceStable(symbols, func(a, b int) bool { return weights[symbols[a]] < weights[symbols[b]] })
+
+		// Листья и внутренние узлы в двух очередях по возрастанию веса
+		type node struct {
+			weight      uint64
+			left, right int
+		}
+		nodes := make([]node, len(symbols), 2*len(symbols)-1)
+		for i, symbol := range symbols {
+			nodes[i] = node{weight: uint64(weights[symbol]), left: -1, right: -1}
+		}
+		leaf, inner := 0, len(symbols)
+		pick := func() int {
+			if leaf < len(symbols) && (inner >= len(nodes) || nodes[leaf].weight <= nodes[inner].weight) {
+				leaf++
+				return leaf - 1
+			}
+			inner++
+			return inner - 1
+		}
+		for len(nodes) < 2*len(symbols)-1 {
+			a, b := pick(), pick()
+			nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight, left: a, right: b})
+		}
+
+		depth := make([]int, len(nodes))
+		maxDepth := 0
+		for i := len(nodes) - 1; i >= len(symbols); i-- {
+			depth[nodes[i].left] = depth[i] + 1
+			depth[nodes[i].right] = depth[i] + 1
+			maxDepth = max(maxDepth, depth[i]+1)
+		}
+
+		if maxDepth <= limit {
+			for i, symbol := range symbols {
+				lengths[symbol] = uint8(depth[i])
+			}
+			return lengths
+		}
+		for _, symbol := range symbols {
+			weights[symbol] = (weights[symbol] + 1) / 2
+		}
+	}
+}
+
+// canonicalCode назначает каноническим кодам длины lengths и разворачивает их биты для записи
+func canonicalCode(lengths []uint8) huffmanCode {
+	code := huffmanCode{codes: make([]uint32, len(lengths)), lengths: make([]uint8, len(lengths))}
+
+	var histogram [maxHuffmanLength + 1]uint32
+	used := 0
+	for _, length := range lengths {
+		if length > 0 {
+			histogram[length]++
+			used++
+		}
+	}
+	if used <= 1 {
+		// Единственный символ декодер читает без бит
+		return code
+	}
+
+	var next [maxHuffmanLength + 1]uint32
+	current := uint32(0)
+	for length := 1; length <= maxHuffmanLength; length++ {
+		current = (current + histogram[length-1]) << 1
+		next[length] = current
+	}
+
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		code.codes[symbol] = bits.Reverse32(next[length]) >> (32 - uint(length))
+		code.lengths[symbol] = length
+		next[length]++
+	}
+	return code
+}
@@ -0,0 +1,143 @@
+package media
+
+import (
+	"bytes"
+	"golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+// roundTrip кодирует изображение в WebP и проверяет, что декодер возвращает те же пиксели
+func roundTrip(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := encodeWebP(&buf, img); err != nil {
+		t.Fatalf("encodeWebP: %v", err)
+	}
+
+	decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("webp.Decode: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if decoded.Bounds().Dx() != bounds.Dx() || decoded.Bounds().Dy() != bounds.Dy() {
+		t.Fatalf("decoded size = %v, want %v", decoded.Bounds().Size(), bounds.Size())
+	}
+
+	want := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(want, want.Bounds(), img, bounds.Min, draw.Src)
+	got := image.NewNRGBA(want.Bounds())
+	draw.Draw(got, got.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+	for i := 0; i < len(want.Pix); i += 4 {
+		// У полностью прозрачных пикселей цвет не важен
+		if want.Pix[i+3] == 0 && got.Pix[i+3] == 0 {
+			continue
+		}
+		if !bytes.Equal(want.Pix[i:i+4], got.Pix[i:i+4]) {
+			x, y := (i/4)%bounds.Dx(), (i/4)/bounds.Dx()
+			t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got.Pix[i:i+4], want.Pix[i:i+4])
+		}
+	}
+	return buf.Bytes()
+}
+
+func noiseImage(width, height int, seed int64, alpha bool) *image.NRGBA {
+	rng := rand.New(rand.NewSource(seed))
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rng.Read(img.Pix)
+	if !alpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+	}
+	return img
+}
+
+func gradientImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 3), G: uint8(y * 2), B: uint8(x + y), A: 0xff})
+		}
+	}
+	return img
+}
+
+func TestEncodeWebPRoundTrip(t *testing.T) {
+	tests := map[string]image.Image{
+		"single pixel":   noiseImage(1, 1, 1, true),
+		"single row":     noiseImage(37, 1, 2, false),
+		"single column":  noiseImage(1, 41, 3, false),
+		"noise":          noiseImage(53, 29, 4, false),
+		"noise alpha":    noiseImage(33, 47, 5, true),
+		"gradient":       gradientImage(130, 70),
+		"solid":          image.NewUniform(color.NRGBA{R: 10, G: 200, B: 30, A: 0xff}),
+		"gray":           image.NewGray(image.Rect(0, 0, 17, 19)),
+		"offset bounds":  gradientImage(40, 40).SubImage(image.Rect(5, 7, 33, 29)),
+		"transparent":    image.NewNRGBA(image.Rect(0, 0, 20, 20)),
+		"long lz77 runs": image.NewUniform(color.Black),
+	}
+
+	for name, img := range tests {
+		t.Run(name, func(t *testing.T) {
+			if uniform, ok := img.(*image.Uniform); ok {
+				// Однотонное изображение превращается в повторы длиннее предельной длины LZ77
+				rgba := image.NewNRGBA(image.Rect(0, 0, 300, 90))
+				draw.Draw(rgba, rgba.Bounds(), uniform, image.Point{}, draw.Src)
+				img = rgba
+			}
+			roundTrip(t, img)
+		})
+	}
+}
+
+func TestEncodeWebPEachPredictor(t *testing.T) {
+	img := noiseImage(48, 40, 6, true)
+	// Плавная нижняя часть, чтобы предсказания отличались от шума
+	for y := 16; y < 40; y++ {
+		for x := 0; x < 48; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * y), G: uint8(x + 2*y), B: uint8(3 * x), A: uint8(200 + x)})
+		}
+	}
+
+	for _, mode := range allPredictors {
+		var buf bytes.Buffer
+		if err := encodeWebPWith(&buf, img, []int{mode}); err != nil {
+			t.Fatalf("mode %d: encodeWebP: %v", mode, err)
+		}
+		decoded, err := webp.Decode(&buf)
+		if err != nil {
+			t.Fatalf("mode %d: webp.Decode: %v", mode, err)
+		}
+		for y := 0; y < 40; y++ {
+			for x := 0; x < 48; x++ {
+				want := img.NRGBAAt(x, y)
+				if got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA); got != want && (want.A != 0 || got.A != 0) {
+					t.Fatalf("mode %d: pixel (%d, %d) = %v, want %v", mode, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeWebPCompresses(t *testing.T) {
+	img := gradientImage(256, 256)
+	encoded := roundTrip(t, img)
+
+	// Плавный градиент почти целиком предсказывается, так что файл должен быть во много раз меньше пикселей
+	if raw := len(img.Pix); len(encoded) > raw/20 {
+		t.Fatalf("webp is %d bytes for %d bytes of pixels", len(encoded), raw)
+	}
+}
+
+func TestEncodeWebPTooLarge(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, webpMaxSize+1, 1))
+	if err := encodeWebP(&bytes.Buffer{}, img); err != errWebPTooLarge {
+		t.Fatalf("encodeWebP error = %v, want errWebPTooLarge", err)
+	}
+}
@@ -2,14 +2,51 @@ package models
 
 import "time"
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+type Role struct {
+	Id          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 type User struct {
 	Id         uint   `json:"id"`
+	RoleId     uint   `json:"roleId" gorm:"default:1"`
+	Role       *Role  `json:"role"`
 	Login      string `json:"login"`
 	Email      string `json:"email"`
 	Password   []byte `json:"-"`
 	Language   string `json:"language"`
 	IsVerified bool   `json:"isVerified"`
 	LogoId     *uint  `json:"logoId"`
+
+	// FailedLogins — неудачные попытки входа подряд, LockedUntil — до какого момента вход заблокирован
+	FailedLogins int        `json:"-"`
+	LockedUntil  *time.Time `json:"-"`
+
+	// TotpSecret — секрет TOTP в base32; до подтверждения настройки TotpEnabled = false.
+	// TotpLastStep — последний принятый временной шаг, повторно тот же код не принимается.
+	TotpSecret   *string `json:"-"`
+	TotpEnabled  bool    `json:"-"`
+	TotpLastStep int64   `json:"-"`
+}
+
+// HasRole проверяет, что пользователю назначена одна из ролей. Роль должна быть загружена через Preload("Role").
+func (user *User) HasRole(names ...string) bool {
+	if user == nil || user.Role == nil {
+		return false
+	}
+	for _, name := range names {
+		if user.Role.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 type VerificationCode struct {
@@ -18,11 +55,51 @@ type VerificationCode struct {
 	Code      string    `json:"code"`
 	Type      string    `json:"type"`
 	ExpiresAt time.Time `json:"expiresAt"`
+	Attempts  int       `json:"-"`
+}
+
+// RecoveryCode — одноразовый код восстановления для входа без приложения TOTP. Хранится только хеш.
+type RecoveryCode struct {
+	Id        uint       `json:"id"`
+	UserId    uint       `json:"userId"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"usedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// Identity — аккаунт у внешнего OIDC провайдера, привязанный к пользователю
+type Identity struct {
+	Id        uint      `json:"id"`
+	UserId    uint      `json:"userId"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"-"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Session struct {
+	Id               uint       `json:"id"`
+	UserId           uint       `json:"userId"`
+	RefreshTokenHash string     `json:"-"`
+	UserAgent        string     `json:"userAgent"`
+	Ip               string     `json:"ip"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	LastSeenAt       time.Time  `json:"lastSeenAt"`
+	ExpiresAt        time.Time  `json:"expiresAt"`
+	RevokedAt        *time.Time `json:"revokedAt"`
+}
+
+// UsedRefreshToken — хеш refresh токена, уже замененного при ротации сессии
+type UsedRefreshToken struct {
+	SessionId uint      `json:"sessionId"`
+	TokenHash string    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Channel struct {
 	Id          uint      `json:"id"`
 	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
 	Description string    `json:"description"`
 	CategoryId  *uint     `json:"-"`
 	Category    *Category `json:"category"`
@@ -31,19 +108,66 @@ type Channel struct {
 	LogoId      *uint     `json:"logoId"`
 }
 
+// ChannelSlugRedirect — старый слаг канала, с которого идет перенаправление после переименования
+type ChannelSlugRedirect struct {
+	Slug      string    `json:"slug" gorm:"primaryKey"`
+	ChannelId uint      `json:"channelId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+)
+
 type Post struct {
-	Id             uint      `json:"id"`
-	ChannelId      uint      `json:"channelId"`
-	PreviewImageId *uint     `json:"previewImage"`
-	Title          string    `json:"title"`
-	Content        string    `json:"content"`
-	LikesCount     uint      `json:"likesCount"`
-	DislikesCount  uint      `json:"dislikesCount"`
-	ViewsCount     uint      `json:"viewsCount"`
-	PostImages     []File    `gorm:"many2many:post_images;" json:"postImages"`
-	PostFiles      []File    `gorm:"many2many:post_files;" json:"postFiles"`
-	Tags           []Tag     `gorm:"many2many:post_tags;" json:"tags"`
-	CreatedAt      time.Time `json:"createdAt"`
+	Id             uint       `json:"id"`
+	ChannelId      uint       `json:"channelId"`
+	PreviewImageId *uint      `json:"previewImage"`
+	Title          string     `json:"title"`
+	Slug           string     `json:"slug"`
+	Content        string     `json:"content"`
+	ContentFormat  string     `json:"contentFormat" gorm:"default:plain"`
+	LikesCount     uint       `json:"likesCount"`
+	DislikesCount  uint       `json:"dislikesCount"`
+	ViewsCount     uint       `json:"viewsCount"`
+	IsHidden       bool       `json:"isHidden"`
+	Status         string     `json:"status"`
+	PublishAt      *time.Time `json:"publishAt"`
+	PublishedAt    *time.Time `json:"publishedAt"`
+	PostImages     []File     `gorm:"many2many:post_images;" json:"postImages"`
+	PostFiles      []File     `gorm:"many2many:post_files;" json:"postFiles"`
+	Tags           []Tag      `gorm:"many2many:post_tags;" json:"tags"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// PostSlugRedirect — старый слаг поста в канале, с которого идет перенаправление после смены заголовка
+type PostSlugRedirect struct {
+	ChannelId uint      `json:"channelId" gorm:"primaryKey"`
+	Slug      string    `json:"slug" gorm:"primaryKey"`
+	PostId    uint      `json:"postId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PostSnapshot — полное состояние поста, сохраняемое в ревизии
+type PostSnapshot struct {
+	Title          string `json:"title"`
+	Content        string `json:"content"`
+	ContentFormat  string `json:"contentFormat,omitempty"`
+	PreviewImageId *uint  `json:"previewImageId"`
+	Tags           []uint `json:"tags"`
+	PostImages     []uint `json:"postImages"`
+	PostFiles      []uint `json:"postFiles"`
+}
+
+type PostRevision struct {
+	Id        uint         `json:"id"`
+	PostId    uint         `json:"postId"`
+	AuthorId  *uint        `json:"authorId"`
+	Snapshot  PostSnapshot `json:"snapshot" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time    `json:"createdAt"`
 }
 
 type Category struct {
@@ -77,6 +201,7 @@ type Comment struct {
 	UserId    uint      `json:"userId"`
 	Content   string    `json:"content"`
 	IsDeleted bool      `json:"isDeleted"`
+	IsHidden  bool      `json:"isHidden"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
@@ -85,11 +210,149 @@ type Subscription struct {
 	ChannelId uint `json:"channelId"`
 }
 
+// SeenPost отмечает, что пользователь уже видел пост в ленте
+type SeenPost struct {
+	UserId uint      `json:"userId"`
+	PostId uint      `json:"postId"`
+	SeenAt time.Time `json:"seenAt"`
+}
+
+type ChannelModerator struct {
+	ChannelId   uint      `json:"channelId"`
+	ModeratorId uint      `json:"moderatorId"`
+	AssignedAt  time.Time `json:"assignedAt"`
+}
+
+type ModerationLog struct {
+	Id         uint      `json:"id"`
+	ActorId    *uint     `json:"actorId"`
+	ChannelId  *uint     `json:"channelId"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"targetType"`
+	TargetId   uint      `json:"targetId"`
+	Details    string    `json:"details"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+const (
+	ComplaintTargetChannel = "channel"
+	ComplaintTargetPost    = "post"
+	ComplaintTargetComment = "comment"
+
+	ComplaintStatusOpen       = "open"
+	ComplaintStatusInProgress = "in progress"
+	ComplaintStatusClosed     = "close"
+)
+
+type Complaint struct {
+	Id            uint       `json:"id"`
+	UserId        uint       `json:"userId"`
+	TargetType    string     `json:"targetType"`
+	TargetId      uint       `json:"targetId"`
+	ComplaintType string     `json:"complaintType"`
+	Description   string     `json:"description"`
+	Status        string     `json:"status" gorm:"default:open"`
+	AssigneeId    *uint      `json:"assigneeId"`
+	Resolution    string     `json:"resolution"`
+	Action        string     `json:"action"`
+	ResolvedAt    *time.Time `json:"resolvedAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// NotificationData — данные для отображения уведомления, сохраненные на момент события
+type NotificationData struct {
+	ActorLogin  string `json:"actorLogin,omitempty"`
+	ChannelName string `json:"channelName,omitempty"`
+	PostTitle   string `json:"postTitle,omitempty"`
+	Text        string `json:"text,omitempty"`
+}
+
+type Notification struct {
+	Id        uint             `json:"id"`
+	UserId    uint             `json:"userId"`
+	Type      string           `json:"type"`
+	ActorId   *uint            `json:"actorId"`
+	ChannelId *uint            `json:"channelId"`
+	PostId    *uint            `json:"postId"`
+	CommentId *uint            `json:"commentId"`
+	Data      NotificationData `json:"data" gorm:"type:jsonb;serializer:json"`
+	IsRead    bool             `json:"isRead"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// NotificationPreference — настройка доставки уведомлений одного типа. Если записи нет,
+// действуют значения по умолчанию.
+type NotificationPreference struct {
+	UserId uint   `json:"userId" gorm:"primaryKey"`
+	Type   string `json:"type" gorm:"primaryKey"`
+	InApp  bool   `json:"inApp"`
+	Email  bool   `json:"email"`
+}
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxEmail — письмо в очереди на отправку
+type OutboxEmail struct {
+	Id            uint       `json:"id"`
+	Recipients    []string   `json:"recipients" gorm:"type:jsonb;serializer:json"`
+	Subject       string     `json:"subject"`
+	Html          string     `json:"html"`
+	Text          string     `json:"text"`
+	Status        string     `json:"status" gorm:"default:pending"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LastError     string     `json:"lastError"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	SentAt        *time.Time `json:"sentAt"`
+}
+
+// Видимость файла: публичный доступен по постоянной ссылке, закрытый — по временной подписанной ссылке
+// или через API тем, кто может видеть его владельца или пост, к которому он прикреплен
+const (
+	FileVisibilityPublic  = "public"
+	FileVisibilityPrivate = "private"
+)
+
 type File struct {
+	Id         uint   `json:"id"`
+	OwnerId    uint   `json:"ownerId"`
+	Filename   string `json:"filename"`
+	MimeType   string `json:"mimeType"`
+	Visibility string `json:"visibility"`
+	// Variants — уменьшенные копии изображения: имя варианта (avatar_128, preview_640) -> объект в хранилище
+	Variants map[string]string `json:"variants" gorm:"type:jsonb;serializer:json"`
+}
+
+// VariantObjects возвращает объекты вариантов в хранилище
+func (file *File) VariantObjects() []string {
+	objects := make([]string, 0, len(file.Variants))
+	for _, object := range file.Variants {
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// PendingUpload — файл, для которого выдана ссылка на загрузку напрямую в хранилище или начата загрузка
+// частями. Запись files создается только после проверки загруженного объекта.
+type PendingUpload struct {
 	Id       uint   `json:"id"`
 	OwnerId  uint   `json:"ownerId"`
 	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
 	MimeType string `json:"mimeType"`
+	// Type — разрешенная категория файла (image, video...), пустая — любая
+	Type string `json:"type"`
+	// MultipartId — идентификатор составной загрузки в MinIO, если файл загружается частями через API
+	MultipartId *string `json:"-"`
+	// ChunkSize — размер каждой части, кроме последней
+	ChunkSize  int64     `json:"chunkSize"`
+	Visibility string    `json:"visibility"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
 
 type ChannelStatistics struct {
@@ -0,0 +1,248 @@
+package notify
+
+import (
+	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/realtime"
+	"gorm.io/gorm"
+	"log"
+	"slices"
+)
+
+// Типы уведомлений
+const (
+	TypeCommentReply  = "comment_reply"
+	TypeNewSubscriber = "new_subscriber"
+	TypeNewPost       = "new_post"
+)
+
+// Types — все типы уведомлений в порядке вывода настроек
+var Types = []string{TypeCommentReply, TypeNewSubscriber, TypeNewPost}
+
+// Preference — способы доставки уведомлений одного типа
+type Preference struct {
+	InApp bool
+	Email bool
+}
+
+// DefaultPreferences действуют, пока пользователь не изменил настройки. Письма о новых постах
+// по умолчанию выключены, чтобы подписка на популярные каналы не заваливала почту.
+var DefaultPreferences = map[string]Preference{
+	TypeCommentReply:  {InApp: true, Email: true},
+	TypeNewSubscriber: {InApp: true, Email: false},
+	TypeNewPost:       {InApp: true, Email: false},
+}
+
+const (
+	queueSize = 1000
+	// recipientsBatch ограничивает число получателей, обрабатываемых одним запросом к базе
+	recipientsBatch = 500
+)
+
+// Event — событие, о котором нужно уведомить пользователей. Получатели — UserIds и подписчики канала
+// SubscribersOf; автор события (ActorId) уведомление о своем действии не получает.
+type Event struct {
+	Type          string
+	UserIds       []uint
+	SubscribersOf *uint
+	ActorId       *uint
+	ChannelId     *uint
+	PostId        *uint
+	CommentId     *uint
+	Data          models.NotificationData
+}
+
+// Dispatcher сохраняет уведомления, передает их подключенным клиентам и рассылает письма в фоне,
+// не задерживая обработку запроса
+type Dispatcher struct {
+	db     *gorm.DB
+	sender mail.EmailSender
+	hub    *realtime.Hub
+	queue  chan Event
+}
+
+func NewDispatcher(db *gorm.DB, sender mail.EmailSender, hub *realtime.Hub) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		sender: sender,
+		hub:    hub,
+		queue:  make(chan Event, queueSize),
+	}
+}
+
+// Start запускает фоновую обработку событий
+func (d *Dispatcher) Start() {
+	go func() {
+		for event := range d.queue {
+			d.deliver(event)
+		}
+	}()
+}
+
+// Notify ставит событие в очередь. Если очередь переполнена, событие отбрасывается.
+func (d *Dispatcher) Notify(event Event) {
+	select {
+	case d.queue <- event:
+	default:
+		log.Printf("Очередь уведомлений переполнена, событие %s отброшено", event.Type)
+	}
+}
+
+// Preferences возвращает настройки пользователя по всем типам с учетом значений по умолчанию
+func (d *Dispatcher) Preferences(userId uint) (map[string]Preference, error) {
+	var stored []models.NotificationPreference
+	if err := d.db.Where("user_id = ?", userId).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[string]Preference, len(Types))
+	for _, notificationType := range Types {
+		preferences[notificationType] = DefaultPreferences[notificationType]
+	}
+	for _, p := range stored {
+		preferences[p.Type] = Preference{InApp: p.InApp, Email: p.Email}
+	}
+	return preferences, nil
+}
+
+func (d *Dispatcher) deliver(event Event) {
+	recipients, err := d.recipients(event)
+	if err != nil {
+		log.Printf("Не удалось определить получателей уведомления %s: %v", event.Type, err)
+		return
+	}
+
+	for start := 0; start < len(recipients); start += recipientsBatch {
+		end := min(start+recipientsBatch, len(recipients))
+		if err = d.deliverBatch(event, recipients[start:end]); err != nil {
+			log.Printf("Не удалось доставить уведомление %s: %v", event.Type, err)
+		}
+	}
+}
+
+func (d *Dispatcher) recipients(event Event) ([]uint, error) {
+	recipients := slices.Clone(event.UserIds)
+
+	if event.SubscribersOf != nil {
+		var subscribers []uint
+		if err := d.db.Model(&models.Subscription{}).
+			Where("channel_id = ?", *event.SubscribersOf).
+			Pluck("user_id", &subscribers).Error; err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, subscribers...)
+	}
+
+	slices.Sort(recipients)
+	recipients = slices.Compact(recipients)
+
+	if event.ActorId != nil {
+		recipients = slices.DeleteFunc(recipients, func(id uint) bool {
+			return id == *event.ActorId
+		})
+	}
+	return recipients, nil
+}
+
+func (d *Dispatcher) deliverBatch(event Event, userIds []uint) error {
+	var stored []models.NotificationPreference
+	if err := d.db.Where("user_id IN ? AND type = ?", userIds, event.Type).Find(&stored).Error; err != nil {
+		return err
+	}
+
+	preferences := make(map[uint]Preference, len(stored))
+	for _, p := range stored {
+		preferences[p.UserId] = Preference{InApp: p.InApp, Email: p.Email}
+	}
+
+	var notifications []models.Notification
+	var emailIds []uint
+	for _, userId := range userIds {
+		preference, ok := preferences[userId]
+		if !ok {
+			preference = DefaultPreferences[event.Type]
+		}
+
+		if preference.InApp {
+			notifications = append(notifications, models.Notification{
+				UserId:    userId,
+				Type:      event.Type,
+				ActorId:   event.ActorId,
+				ChannelId: event.ChannelId,
+				PostId:    event.PostId,
+				CommentId: event.CommentId,
+				Data:      event.Data,
+			})
+		}
+		if preference.Email {
+			emailIds = append(emailIds, userId)
+		}
+	}
+
+	if len(notifications) > 0 {
+		if err := d.db.Create(&notifications).Error; err != nil {
+			return err
+		}
+		for _, notification := range notifications {
+			d.hub.Publish(realtime.UserTopic(notification.UserId), realtime.EventNotificationCreated, notification)
+		}
+	}
+
+	if len(emailIds) > 0 {
+		d.sendEmails(event, emailIds)
+	}
+	return nil
+}
+
+func (d *Dispatcher) sendEmails(event Event, userIds []uint) {
+	var users []models.User
+	if err := d.db.Select("id", "email", "language").Where("id IN ? AND is_verified = TRUE", userIds).Find(&users).Error; err != nil {
+		log.Printf("Не удалось получить адреса для уведомления %s: %v", event.Type, err)
+		return
+	}
+
+	// Письмо собирается один раз на каждый язык получателей
+	messages := make(map[string]mail.Message)
+	for _, user := range users {
+		message, ok := messages[user.Language]
+		if !ok {
+			var err error
+			if message, err = mail.Render(event.Type, user.Language, event.Data); err != nil {
+				log.Printf("Не удалось собрать письмо %s: %v", event.Type, err)
+				return
+			}
+			messages[user.Language] = message
+		}
+
+		if err := d.sender.SendMessage(message, []string{user.Email}); err != nil {
+			log.Printf("Не удалось отправить уведомление %s пользователю %d: %v", event.Type, user.Id, err)
+		}
+	}
+}
+
+// PostPublished уведомляет подписчиков канала о публикации поста. Владелец канала уведомление не получает.
+func (d *Dispatcher) PostPublished(post models.Post, channel models.Channel) {
+	d.Notify(Event{
+		Type:          TypeNewPost,
+		SubscribersOf: &channel.Id,
+		ActorId:       &channel.OwnerId,
+		ChannelId:     &channel.Id,
+		PostId:        &post.Id,
+		Data: models.NotificationData{
+			ChannelName: channel.Name,
+			PostTitle:   post.Title,
+		},
+	})
+}
+
+// excerptLength — сколько символов текста комментария сохраняется в уведомлении
+const excerptLength = 200
+
+// Excerpt обрезает текст для показа в уведомлении
+func Excerpt(text string) string {
+	runes := []rune(text)
+	if len(runes) <= excerptLength {
+		return text
+	}
+	return string(runes[:excerptLength]) + "…"
+}
@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 50
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit")
+)
+
+// Cursor указывает на последний элемент страницы. Клиенту он передается непрозрачной строкой.
+// Time или Score — значение поля сортировки, Id разрешает равенство значений.
+type Cursor struct {
+	Time  *time.Time `json:"t,omitempty"`
+	Score *float64   `json:"s,omitempty"`
+	Id    uint       `json:"id"`
+}
+
+// Kind — тип поля сортировки, которое хранит курсор
+type Kind int
+
+const (
+	ByTime Kind = iota
+	ByScore
+)
+
+// Params — разобранные параметры запроса страницы
+type Params struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+// Parse разбирает параметры cursor и limit. Пустой cursor означает первую страницу,
+// курсор другого вида (например, по времени вместо рейтинга) считается невалидным.
+func Parse(cursor string, limit string, kind Kind) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			return params, ErrInvalidLimit
+		}
+		params.Limit = min(parsed, MaxLimit)
+	}
+
+	if cursor != "" {
+		decoded, err := Decode(cursor)
+		if err != nil {
+			return params, err
+		}
+		if (kind == ByTime && decoded.Time == nil) || (kind == ByScore && decoded.Score == nil) {
+			return params, ErrInvalidCursor
+		}
+		params.Cursor = decoded
+	}
+
+	return params, nil
+}
+
+// Encode упаковывает курсор в строку для ответа
+func Encode(cursor Cursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode распаковывает курсор, полученный от клиента
+func Decode(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err = json.Unmarshal(raw, &cursor); err != nil || cursor.Id == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// TimeCursor создает курсор для сортировки по времени
+func TimeCursor(t time.Time, id uint) Cursor {
+	return Cursor{Time: &t, Id: id}
+}
+
+// ScoreCursor создает курсор для сортировки по числовому значению
+func ScoreCursor(score float64, id uint) Cursor {
+	return Cursor{Score: &score, Id: id}
+}
+
+// Trim отрезает лишний элемент, запрошенный сверх Limit, и сообщает, есть ли следующая страница.
+// Запросы выбирают Limit+1 строк, чтобы узнать hasMore без отдельного COUNT.
+func Trim[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
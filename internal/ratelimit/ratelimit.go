@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Rule — не больше Limit запросов за окно Window
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Result — состояние счетчика после запроса
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// Store считает запросы по ключам. Increment увеличивает счетчик ключа в текущем окне и возвращает
+// новое значение и время окончания окна. Чтобы несколько экземпляров приложения делили лимиты,
+// MemoryStore заменяется хранилищем поверх общей базы или кеша.
+type Store interface {
+	Increment(key string, window time.Duration) (count int, resetAt time.Time, err error)
+}
+
+// Limiter ограничивает частоту запросов по правилу
+type Limiter struct {
+	store Store
+	rule  Rule
+	name  string
+}
+
+// New создает лимитер. Имя входит в ключ, поэтому лимитеры разных эндпоинтов не мешают друг другу.
+func New(store Store, name string, rule Rule) *Limiter {
+	return &Limiter{store: store, rule: rule, name: name}
+}
+
+// Allow учитывает запрос с ключом key (IP, аккаунт) и сообщает, укладывается ли он в лимит.
+// Если хранилище недоступно, запрос пропускается: ограничение частоты не должно ломать вход.
+func (l *Limiter) Allow(key string) Result {
+	count, resetAt, err := l.store.Increment(l.name+":"+key, l.rule.Window)
+	if err != nil {
+		return Result{Allowed: true, Limit: l.rule.Limit, Remaining: l.rule.Limit, ResetAt: time.Now().Add(l.rule.Window)}
+	}
+
+	return Result{
+		Allowed:   count <= l.rule.Limit,
+		Limit:     l.rule.Limit,
+		Remaining: max(l.rule.Limit-count, 0),
+		ResetAt:   resetAt,
+	}
+}
+
+type window struct {
+	count   int
+	resetAt time.Time
+}
+
+// MemoryStore хранит счетчики в памяти процесса с фиксированными окнами
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+// sweepInterval — как часто из памяти удаляются закончившиеся окна
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		windows:   make(map[string]*window),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Increment(key string, duration time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		for k, w := range s.windows {
+			if !now.Before(w.resetAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &window{resetAt: now.Add(duration)}
+		s.windows[key] = w
+	}
+	w.count++
+
+	return w.count, w.resetAt, nil
+}
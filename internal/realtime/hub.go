@@ -0,0 +1,180 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+)
+
+// subscriberBuffer — сколько событий может ждать отправки одному клиенту. Клиент, который
+// не успевает их забирать, отключается и должен переподключиться.
+const subscriberBuffer = 64
+
+// Event — событие, доставляемое клиентам, подписанным на тему
+type Event struct {
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Типы событий
+const (
+	EventCommentCreated      = "comment.created"
+	EventReactionUpdated     = "reaction.updated"
+	EventNotificationCreated = "notification.created"
+)
+
+// PostTopic — тема событий поста: новые комментарии и счетчики реакций
+func PostTopic(postId uint) string {
+	return "post:" + strconv.FormatUint(uint64(postId), 10)
+}
+
+// UserTopic — личная тема пользователя: новые уведомления
+func UserTopic(userId uint) string {
+	return "user:" + strconv.FormatUint(uint64(userId), 10)
+}
+
+// Broker рассылает события между экземплярами приложения. Publish передает событие всем
+// экземплярам, включая текущий; каждый экземпляр получает события через функцию из Subscribe.
+type Broker interface {
+	Publish(event Event) error
+	Subscribe(deliver func(event Event))
+}
+
+// LocalBroker доставляет события только внутри текущего процесса
+type LocalBroker struct {
+	mu       sync.RWMutex
+	handlers []func(event Event)
+}
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{}
+}
+
+func (b *LocalBroker) Publish(event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, deliver := range b.handlers {
+		deliver(event)
+	}
+	return nil
+}
+
+func (b *LocalBroker) Subscribe(deliver func(event Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, deliver)
+}
+
+// Subscription — подписка клиента на набор тем
+type Subscription struct {
+	topics []string
+	events chan Event
+	closed bool
+}
+
+// Events возвращает канал событий. Канал закрывается при отписке или если клиент отстал.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Hub хранит подписки клиентов текущего экземпляра и раздает им события из брокера
+type Hub struct {
+	broker Broker
+
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+func NewHub(broker Broker) *Hub {
+	hub := &Hub{
+		broker: broker,
+		topics: make(map[string]map[*Subscription]struct{}),
+	}
+	broker.Subscribe(hub.dispatch)
+	return hub
+}
+
+// Publish отправляет событие всем подписчикам темы. Ошибки только логируются: доставка
+// в реальном времени не должна ломать запрос, который ее вызвал.
+func (h *Hub) Publish(topic string, eventType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Не удалось сериализовать событие %s: %v", eventType, err)
+		return
+	}
+
+	if err = h.broker.Publish(Event{Topic: topic, Type: eventType, Data: payload}); err != nil {
+		log.Printf("Не удалось опубликовать событие %s в %s: %v", eventType, topic, err)
+	}
+}
+
+// Subscribe подписывает клиента на темы. После завершения подписку нужно снять через Unsubscribe.
+func (h *Hub) Subscribe(topics []string) *Subscription {
+	subscription := &Subscription{
+		topics: topics,
+		events: make(chan Event, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*Subscription]struct{})
+		}
+		h.topics[topic][subscription] = struct{}{}
+	}
+	return subscription
+}
+
+// Unsubscribe снимает подписку и закрывает ее канал событий
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(subscription)
+}
+
+// dispatch раздает событие подписчикам темы, не блокируясь на медленных клиентах
+func (h *Hub) dispatch(event Event) {
+	h.mu.RLock()
+	var lagging []*Subscription
+	for subscription := range h.topics[event.Topic] {
+		select {
+		case subscription.events <- event:
+		default:
+			lagging = append(lagging, subscription)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(lagging) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subscription := range lagging {
+		h.remove(subscription)
+	}
+}
+
+// remove вызывается под блокировкой записи
+func (h *Hub) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+
+	for _, topic := range subscription.topics {
+		delete(h.topics[topic], subscription)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	close(subscription.events)
+}
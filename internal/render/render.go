@@ -0,0 +1,122 @@
+package render
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Форматы содержимого постов
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// ImageScheme — префикс ссылки на изображение, прикрепленное к посту: ![подпись](image:12)
+const ImageScheme = "image:"
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy пропускает обычную разметку, ссылки и изображения по http(s),
+	// но вырезает скрипты, стили, обработчики событий и прочий HTML
+	policy = bluemonday.UGCPolicy()
+)
+
+// IsValidFormat сообщает, поддерживается ли формат содержимого
+func IsValidFormat(format string) bool {
+	return format == FormatPlain || format == FormatMarkdown
+}
+
+// HTML превращает содержимое в безопасный HTML. Ссылки image:Id заменяются адресами из images,
+// изображения с неизвестным Id удаляются. Неизвестный формат обрабатывается как plain.
+func HTML(content string, format string, images map[uint]string) string {
+	if format == FormatMarkdown {
+		rendered, err := renderMarkdown(content, images)
+		if err == nil {
+			return policy.Sanitize(rendered)
+		}
+		log.Printf("Не удалось отрендерить markdown: %v", err)
+	}
+
+	return policy.Sanitize(renderPlain(content))
+}
+
+// renderPlain экранирует текст, разбивает его на абзацы по пустым строкам и сохраняет переносы строк
+func renderPlain(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var builder strings.Builder
+	for _, paragraph := range strings.Split(content, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+
+		builder.WriteString("<p>")
+		builder.WriteString(strings.Join(lines, "<br>"))
+		builder.WriteString("</p>\n")
+	}
+
+	return builder.String()
+}
+
+// renderMarkdown рендерит markdown, подставляя адреса прикрепленных изображений.
+// Сырой HTML внутри markdown goldmark не выводит.
+func renderMarkdown(content string, images map[uint]string) (string, error) {
+	source := []byte(content)
+	document := markdown.Parser().Parse(text.NewReader(source))
+
+	var unresolved []ast.Node
+	err := ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		image, ok := node.(*ast.Image)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		destination := string(image.Destination)
+		if !strings.HasPrefix(destination, ImageScheme) {
+			return ast.WalkContinue, nil
+		}
+
+		id, err := strconv.ParseUint(strings.TrimPrefix(destination, ImageScheme), 10, 64)
+		url, found := images[uint(id)]
+		if err != nil || !found {
+			unresolved = append(unresolved, image)
+			return ast.WalkSkipChildren, nil
+		}
+
+		image.Destination = []byte(url)
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, node := range unresolved {
+		node.Parent().RemoveChild(node.Parent(), node)
+	}
+
+	var buffer bytes.Buffer
+	if err = markdown.Renderer().Render(&buffer, source, document); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
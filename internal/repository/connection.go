@@ -1,28 +1,18 @@
 package repository
 
 import (
+	"blogpoint-backend/internal/config"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"os"
 	"time"
 )
 
 var DB *gorm.DB
 
-func Connect() {
-
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT")
-
-	fmt.Println(port)
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", host, user, password, dbname, port)
-
-	fmt.Println(dsn)
+// Connect открывает подключение к базе, дожидаясь ее готовности
+func Connect(cfg config.DatabaseConfig) {
+	dsn := cfg.DSN()
 
 	var err error
 	for i := 0; i < 10; i++ {
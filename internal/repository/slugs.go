@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/slug"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"time"
+)
+
+// UniqueChannelSlug подбирает свободный слаг канала по названию. Занятыми считаются слаги других каналов
+// и старые слаги, с которых идет перенаправление на другие каналы.
+func UniqueChannelSlug(db *gorm.DB, name string, channelId uint) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "channel"
+	}
+
+	return slug.Unique(base, func(candidate string) (bool, error) {
+		var count int64
+		err := db.Raw(`SELECT
+			(SELECT COUNT(*) FROM channels WHERE slug = ? AND id <> ?) +
+			(SELECT COUNT(*) FROM channel_slug_redirects WHERE slug = ? AND channel_id <> ?)`,
+			candidate, channelId, candidate, channelId).Scan(&count).Error
+		return count > 0, err
+	})
+}
+
+// UniquePostSlug подбирает свободный в пределах канала слаг поста по заголовку
+func UniquePostSlug(db *gorm.DB, title string, channelId uint, postId uint) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = "post"
+	}
+
+	return slug.Unique(base, func(candidate string) (bool, error) {
+		var count int64
+		err := db.Raw(`SELECT
+			(SELECT COUNT(*) FROM posts WHERE channel_id = ? AND slug = ? AND id <> ?) +
+			(SELECT COUNT(*) FROM post_slug_redirects WHERE channel_id = ? AND slug = ? AND post_id <> ?)`,
+			channelId, candidate, postId, channelId, candidate, postId).Scan(&count).Error
+		return count > 0, err
+	})
+}
+
+// SaveChannelSlugRedirect запоминает старый слаг канала, чтобы перенаправлять с него на текущий
+func SaveChannelSlugRedirect(db *gorm.DB, oldSlug string, channelId uint) error {
+	if oldSlug == "" {
+		return nil
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channel_id", "created_at"}),
+	}).Create(&models.ChannelSlugRedirect{
+		Slug:      oldSlug,
+		ChannelId: channelId,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// SavePostSlugRedirect запоминает старый слаг поста, чтобы перенаправлять с него на текущий
+func SavePostSlugRedirect(db *gorm.DB, oldSlug string, channelId uint, postId uint) error {
+	if oldSlug == "" {
+		return nil
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "channel_id"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"post_id", "created_at"}),
+	}).Create(&models.PostSlugRedirect{
+		ChannelId: channelId,
+		Slug:      oldSlug,
+		PostId:    postId,
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// BackfillSlugs заполняет слаги каналов и постов, созданных до их появления
+func BackfillSlugs() {
+	var channels []models.Channel
+	if err := DB.Where("slug IS NULL OR slug = ''").Order("id").Find(&channels).Error; err != nil {
+		log.Printf("Не удалось получить каналы без слага: %v", err)
+		return
+	}
+
+	for _, channel := range channels {
+		channelSlug, err := UniqueChannelSlug(DB, channel.Name, channel.Id)
+		if err == nil {
+			err = DB.Model(&channel).Update("slug", channelSlug).Error
+		}
+		if err != nil {
+			log.Printf("Не удалось заполнить слаг канала %d: %v", channel.Id, err)
+		}
+	}
+
+	var posts []models.Post
+	if err := DB.Select("id", "channel_id", "title").
+		Where("slug IS NULL OR slug = ''").Order("id").Find(&posts).Error; err != nil {
+		log.Printf("Не удалось получить посты без слага: %v", err)
+		return
+	}
+
+	for _, post := range posts {
+		postSlug, err := UniquePostSlug(DB, post.Title, post.ChannelId, post.Id)
+		if err == nil {
+			err = DB.Model(&post).Update("slug", postSlug).Error
+		}
+		if err != nil {
+			log.Printf("Не удалось заполнить слаг поста %d: %v", post.Id, err)
+		}
+	}
+
+	if len(channels) > 0 || len(posts) > 0 {
+		log.Printf("Заполнены слаги: каналов %d, постов %d", len(channels), len(posts))
+	}
+}
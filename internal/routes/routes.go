@@ -1,64 +1,176 @@
 package routes
 
 import (
+	"blogpoint-backend/internal/config"
 	"blogpoint-backend/internal/controllers"
 	"blogpoint-backend/internal/mail"
+	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/notify"
+	"blogpoint-backend/internal/ratelimit"
+	"blogpoint-backend/internal/realtime"
+	"blogpoint-backend/internal/repository"
+	"blogpoint-backend/internal/search"
+	"blogpoint-backend/internal/sso"
 	"github.com/gofiber/fiber/v2"
+	"time"
 )
 
-func Setup(app *fiber.App, emailSender mail.EmailSender) {
+func Setup(app *fiber.App, cfg *config.Config, emailSender mail.EmailSender, notifier *notify.Dispatcher, hub *realtime.Hub) {
 
-	app.Post("/api/register", controllers.Register)
-	app.Post("/api/login", controllers.Login)
-	app.Post("/api/logout", controllers.Logout)
-	app.Get("/api/user", controllers.User)
-	app.Patch("/api/editProfile", controllers.EditProfile)
-	app.Patch("/api/changePassword", controllers.ChangePassword)
-	app.Patch("/api/languageUpdate", controllers.LanguageUpdate)
-	app.Post("/api/uploadUserLogo", controllers.UploadUserLogo)
-	app.Delete("/api/deleteUserLogo", controllers.DeleteUserLogo)
+	controllers.Configure(cfg.Auth)
 
-	app.Post("/api/requestEmailVerification", func(c *fiber.Ctx) error {
-		return controllers.RequestEmailVerification(c, emailSender)
+	searchEngine := search.NewPostgresEngine(repository.DB)
+	providers := sso.NewRegistry(cfg.Auth.OIDC)
+
+	auth := controllers.RequireAuth
+	optionalAuth := controllers.OptionalAuth
+	adminOnly := controllers.RequireRole(models.RoleAdmin)
+	staffOnly := controllers.RequireRole(models.RoleModerator, models.RoleAdmin)
+
+	// Ограничения частоты для эндпоинтов авторизации: по IP в middleware и по аккаунту в обработчиках писем
+	limits := ratelimit.NewMemoryStore()
+	loginLimit := controllers.RateLimit(ratelimit.New(limits, "login", ratelimit.Rule{Limit: 10, Window: time.Minute}))
+	registerLimit := controllers.RateLimit(ratelimit.New(limits, "register", ratelimit.Rule{Limit: 10, Window: time.Hour}))
+	resetRequestLimit := controllers.RateLimit(ratelimit.New(limits, "password_reset", ratelimit.Rule{Limit: 5, Window: 15 * time.Minute}))
+	codeCheckLimit := controllers.RateLimit(ratelimit.New(limits, "code_check", ratelimit.Rule{Limit: 10, Window: time.Minute}))
+	codeMailLimiter := ratelimit.New(limits, "code_mail", ratelimit.Rule{Limit: 3, Window: 10 * time.Minute})
+
+	app.Post("/api/register", registerLimit, controllers.Register)
+	app.Post("/api/login", loginLimit, controllers.Login)
+	app.Post("/api/loginMfa", codeCheckLimit, controllers.LoginMfa)
+	app.Get("/api/oidc/providers", func(c *fiber.Ctx) error {
+		return controllers.GetOIDCProviders(c, providers)
+	})
+	app.Get("/api/oidc/:provider/login", loginLimit, optionalAuth, func(c *fiber.Ctx) error {
+		return controllers.OIDCLogin(c, providers)
+	})
+	app.Get("/api/oidc/:provider/callback", loginLimit, optionalAuth, func(c *fiber.Ctx) error {
+		return controllers.OIDCCallback(c, providers)
+	})
+	app.Post("/api/logout", optionalAuth, controllers.Logout)
+	app.Post("/api/refresh", controllers.Refresh)
+	app.Get("/api/getSessions", auth, controllers.GetSessions)
+	app.Delete("/api/revokeSession/:id", auth, controllers.RevokeSession)
+	app.Delete("/api/revokeOtherSessions", auth, controllers.RevokeOtherSessions)
+	app.Get("/api/getIdentities", auth, controllers.GetIdentities)
+	app.Delete("/api/unlinkIdentity/:id", auth, controllers.UnlinkIdentity)
+	app.Post("/api/setupTotp", auth, controllers.SetupTotp)
+	app.Post("/api/confirmTotp", codeCheckLimit, auth, controllers.ConfirmTotp)
+	app.Post("/api/disableTotp", codeCheckLimit, auth, controllers.DisableTotp)
+	app.Post("/api/regenerateRecoveryCodes", codeCheckLimit, auth, controllers.RegenerateRecoveryCodes)
+	app.Get("/api/user", auth, controllers.User)
+	app.Patch("/api/editProfile", auth, controllers.EditProfile)
+	app.Patch("/api/changePassword", auth, controllers.ChangePassword)
+	app.Patch("/api/languageUpdate", auth, controllers.LanguageUpdate)
+	app.Post("/api/uploadUserLogo", auth, controllers.UploadUserLogo)
+	app.Delete("/api/deleteUserLogo", auth, controllers.DeleteUserLogo)
+
+	app.Post("/api/requestEmailVerification", auth, func(c *fiber.Ctx) error {
+		return controllers.RequestEmailVerification(c, emailSender, codeMailLimiter)
 	})
-	app.Post("/api/verifyEmail", controllers.VerifyEmail)
-	app.Post("/api/requestPasswordReset", func(c *fiber.Ctx) error {
-		return controllers.RequestPasswordReset(c, emailSender)
+	app.Post("/api/verifyEmail", codeCheckLimit, auth, controllers.VerifyEmail)
+	app.Post("/api/requestPasswordReset", resetRequestLimit, func(c *fiber.Ctx) error {
+		return controllers.RequestPasswordReset(c, emailSender, codeMailLimiter)
 	})
-	app.Patch("/api/resetPassword", controllers.ResetPassword)
-	app.Post("/api/requestDeletionVerification", func(c *fiber.Ctx) error {
-		return controllers.RequestDeletionVerification(c, emailSender)
+	app.Patch("/api/resetPassword", codeCheckLimit, optionalAuth, controllers.ResetPassword)
+	app.Post("/api/requestDeletionVerification", auth, func(c *fiber.Ctx) error {
+		return controllers.RequestDeletionVerification(c, emailSender, codeMailLimiter)
 	})
-	app.Delete("/api/deleteUser", controllers.DeleteUser)
+	app.Delete("/api/deleteUser", codeCheckLimit, auth, controllers.DeleteUser)
 
-	app.Post("/api/createChannel", controllers.CreateChannel)
-	app.Patch("/api/editChannel", controllers.EditChannel)
-	app.Delete("/api/deleteChannel/:id", controllers.DeleteChannel)
-	app.Get("/api/getUserSubscriptions", controllers.GetUserSubscriptions)
-	app.Get("/api/getUserChannels", controllers.GetUserChannels)
+	app.Get("/api/admin/getRoles", auth, adminOnly, controllers.GetRoles)
+	app.Patch("/api/admin/setUserRole", auth, adminOnly, controllers.SetUserRole)
+
+	app.Post("/api/createChannel", auth, controllers.CreateChannel)
+	app.Patch("/api/editChannel", auth, controllers.EditChannel)
+	app.Delete("/api/deleteChannel/:id", auth, controllers.DeleteChannel)
+	app.Get("/api/getUserSubscriptions", auth, controllers.GetUserSubscriptions)
+	app.Get("/api/getUserChannels", auth, controllers.GetUserChannels)
 	app.Get("/api/getChannel/:id", controllers.GetChannel)
+	app.Get("/api/getChannelBySlug/:slug", controllers.GetChannelBySlug)
 	app.Get("/api/getPopularChannels", controllers.GetPopularChannels)
-	app.Post("/api/subscribeChannel/:id", controllers.SubscribeChannel)
-	app.Delete("/api/unsubscribeChannel/:id", controllers.UnsubscribeChannel)
-	app.Get("/api/getChannelStatistics/:id", controllers.GetChannelStatistics)
-	app.Post("/api/uploadChannelLogo/:id", controllers.UploadChannelLogo)
-	app.Delete("/api/deleteChannelLogo/:id", controllers.DeleteChannelLogo)
+	app.Post("/api/subscribeChannel/:id", auth, func(c *fiber.Ctx) error {
+		return controllers.SubscribeChannel(c, notifier)
+	})
+	app.Delete("/api/unsubscribeChannel/:id", auth, controllers.UnsubscribeChannel)
+	app.Get("/api/getChannelStatistics/:id", auth, controllers.GetChannelStatistics)
+	app.Post("/api/uploadChannelLogo/:id", auth, controllers.UploadChannelLogo)
+	app.Delete("/api/deleteChannelLogo/:id", auth, controllers.DeleteChannelLogo)
+
+	app.Post("/api/addChannelModerator", auth, controllers.AddChannelModerator)
+	app.Delete("/api/removeChannelModerator/:channelId/:userId", auth, controllers.RemoveChannelModerator)
+	app.Get("/api/getChannelModerators/:id", auth, controllers.GetChannelModerators)
+	app.Get("/api/getModerationLog/:id", auth, controllers.GetModerationLog)
+
+	app.Get("/api/search", optionalAuth, func(c *fiber.Ctx) error {
+		return controllers.Search(c, searchEngine)
+	})
 
 	app.Get("/api/getAllCategories", controllers.GetAllCategories)
 	app.Get("/api/getAllTags", controllers.GetAllTags)
 
-	app.Post("/api/createPost", controllers.CreatePost)
-	app.Patch("/api/editPost", controllers.EditPost)
-	app.Delete("/api/deletePost/:id", controllers.DeletePost)
-	app.Get("/api/getPost/:id", controllers.GetPost)
-	app.Get("/api/getPosts/:channelId", controllers.GetPosts)
+	app.Post("/api/createPost", auth, func(c *fiber.Ctx) error {
+		return controllers.CreatePost(c, notifier)
+	})
+	app.Patch("/api/editPost", auth, controllers.EditPost)
+	app.Delete("/api/deletePost/:id", auth, controllers.DeletePost)
+	app.Patch("/api/setPostHidden", auth, controllers.SetPostHidden)
+	app.Patch("/api/setPostStatus", auth, func(c *fiber.Ctx) error {
+		return controllers.SetPostStatus(c, notifier)
+	})
+	app.Get("/api/getPostRevisions/:id", auth, controllers.GetPostRevisions)
+	app.Get("/api/getPostRevisionDiff/:id", auth, controllers.GetPostRevisionDiff)
+	app.Post("/api/restorePostRevision", auth, controllers.RestorePostRevision)
+	app.Get("/api/getPost/:id", optionalAuth, controllers.GetPost)
+	app.Get("/api/getPostBySlug/:channelSlug/:postSlug", optionalAuth, controllers.GetPostBySlug)
+	app.Get("/api/getPosts/:channelId", optionalAuth, controllers.GetPosts)
 	app.Get("/api/getRecommendedPosts", controllers.GetRecommendedPosts)
-	app.Post("/api/setReaction", controllers.SetReaction)
+	app.Get("/api/feed", auth, controllers.GetFeed)
+	app.Post("/api/markFeedSeen", auth, controllers.MarkFeedSeen)
+	app.Post("/api/setReaction", auth, func(c *fiber.Ctx) error {
+		return controllers.SetReaction(c, hub)
+	})
+
+	app.Post("/api/createComment", auth, func(c *fiber.Ctx) error {
+		return controllers.CreateComment(c, notifier, hub)
+	})
+	app.Get("/api/getPostComments", optionalAuth, controllers.GetPostComments)
+	app.Delete("/api/deleteComment/:id", auth, controllers.DeleteComment)
+
+	app.Post("/api/createComplaint", auth, controllers.CreateComplaint)
+	app.Get("/api/getComplaints", auth, staffOnly, controllers.GetComplaints)
+	app.Patch("/api/claimComplaint/:id", auth, staffOnly, controllers.ClaimComplaint)
+	app.Patch("/api/resolveComplaint", auth, staffOnly, func(c *fiber.Ctx) error {
+		return controllers.ResolveComplaint(c, emailSender)
+	})
 
-	app.Post("/api/createComment", controllers.CreateComment)
-	app.Get("/api/getPostComments", controllers.GetPostComments)
-	app.Delete("/api/deleteComment/:id", controllers.DeleteComment)
+	app.Get("/api/getNotifications", auth, controllers.GetNotifications)
+	app.Get("/api/getUnreadNotificationsCount", auth, controllers.GetUnreadNotificationsCount)
+	app.Patch("/api/markNotificationsRead", auth, controllers.MarkNotificationsRead)
+	app.Get("/api/getNotificationPreferences", auth, func(c *fiber.Ctx) error {
+		return controllers.GetNotificationPreferences(c, notifier)
+	})
+	app.Patch("/api/updateNotificationPreferences", auth, func(c *fiber.Ctx) error {
+		return controllers.UpdateNotificationPreferences(c, notifier)
+	})
+
+	app.Get("/api/stream", optionalAuth, func(c *fiber.Ctx) error {
+		return controllers.Stream(c, hub)
+	})
 
-	app.Post("/api/uploadFile", controllers.UploadFile)
-	app.Delete("/api/deleteFile/:id", controllers.DeleteFile)
+	app.Post("/api/uploadFile", auth, controllers.UploadFile)
+	app.Delete("/api/deleteFile/:id", auth, controllers.DeleteFile)
+	app.Patch("/api/setFileVisibility/:id", auth, controllers.SetFileVisibility)
+	app.Get("/api/getFileContent/:id", optionalAuth, controllers.GetFileContent)
+	app.Post("/api/requestUpload", auth, func(c *fiber.Ctx) error {
+		return controllers.RequestUpload(c, int64(cfg.Minio.DirectUploadMaxMB)<<20)
+	})
+	app.Post("/api/completeUpload/:id", auth, controllers.CompleteUpload)
+	app.Post("/api/startChunkedUpload", auth, func(c *fiber.Ctx) error {
+		return controllers.StartChunkedUpload(c, int64(cfg.Minio.DirectUploadMaxMB)<<20)
+	})
+	app.Put("/api/uploadChunk/:id/:chunk", auth, controllers.UploadChunk)
+	app.Get("/api/getChunkedUpload/:id", auth, controllers.GetChunkedUpload)
+	app.Post("/api/completeChunkedUpload/:id", auth, controllers.CompleteChunkedUpload)
+	app.Delete("/api/abortChunkedUpload/:id", auth, controllers.AbortChunkedUpload)
 }
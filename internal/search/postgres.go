@@ -0,0 +1,87 @@
+package search
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+const headlineOptions = "StartSel=" + HighlightStart + ", StopSel=" + HighlightStop +
+	", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+
+type postgresEngine struct {
+	db *gorm.DB
+}
+
+// NewPostgresEngine создает движок поверх сгенерированных tsvector колонок search_ru и search_en
+func NewPostgresEngine(db *gorm.DB) Engine {
+	return &postgresEngine{db: db}
+}
+
+// textSearchConfig возвращает конфигурацию словаря и колонку с индексом для языка
+func textSearchConfig(language string) (string, string) {
+	if NormalizeLanguage(language) == LanguageEnglish {
+		return "english", "search_en"
+	}
+	return "russian", "search_ru"
+}
+
+func (engine *postgresEngine) SearchPosts(ctx context.Context, query Query) ([]PostHit, error) {
+	config, column := textSearchConfig(query.Language)
+
+	tsQuery := engine.db.Raw("SELECT websearch_to_tsquery(?::regconfig, ?) AS q", config, query.Text)
+
+	db := engine.db.WithContext(ctx).
+		Table("posts, (?) AS search", tsQuery).
+		Select(`posts.id AS post_id, posts.channel_id, posts.title, posts.published_at AS created_at,
+			ts_rank(posts.`+column+`, search.q) AS rank,
+			ts_headline(?::regconfig, posts.content, search.q, ?) AS snippet`, config, headlineOptions).
+		Where("posts." + column + " @@ search.q").
+		Where("posts.status = 'published' AND posts.is_hidden = FALSE")
+
+	if query.ChannelId != nil {
+		db = db.Where("posts.channel_id = ?", *query.ChannelId)
+	}
+	if query.CategoryId != nil {
+		db = db.Where("posts.channel_id IN (SELECT id FROM channels WHERE category_id = ?)", *query.CategoryId)
+	}
+	if query.TagId != nil {
+		db = db.Where("EXISTS (SELECT 1 FROM post_tags WHERE post_tags.post_id = posts.id AND post_tags.tag_id = ?)", *query.TagId)
+	}
+	if query.From != nil {
+		db = db.Where("posts.published_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("posts.published_at < ?", *query.To)
+	}
+
+	var hits []PostHit
+	err := db.Order("rank DESC, posts.id DESC").
+		Limit(query.Limit).Offset(query.Offset).
+		Scan(&hits).Error
+
+	return hits, err
+}
+
+func (engine *postgresEngine) SearchChannels(ctx context.Context, query Query) ([]ChannelHit, error) {
+	config, column := textSearchConfig(query.Language)
+
+	tsQuery := engine.db.Raw("SELECT websearch_to_tsquery(?::regconfig, ?) AS q", config, query.Text)
+
+	db := engine.db.WithContext(ctx).
+		Table("channels, (?) AS search", tsQuery).
+		Select(`channels.id AS channel_id, channels.name,
+			ts_rank(channels.`+column+`, search.q) AS rank,
+			ts_headline(?::regconfig, channels.description, search.q, ?) AS snippet`, config, headlineOptions).
+		Where("channels." + column + " @@ search.q")
+
+	if query.CategoryId != nil {
+		db = db.Where("channels.category_id = ?", *query.CategoryId)
+	}
+
+	var hits []ChannelHit
+	err := db.Order("rank DESC, channels.subs_count DESC, channels.id DESC").
+		Limit(query.Limit).Offset(query.Offset).
+		Scan(&hits).Error
+
+	return hits, err
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"context"
+	"time"
+)
+
+// Языки, для которых поддерживается стемминг
+const (
+	LanguageRussian = "ru"
+	LanguageEnglish = "en"
+)
+
+// Query описывает поисковый запрос и фильтры
+type Query struct {
+	Text       string
+	Language   string
+	CategoryId *uint
+	TagId      *uint
+	ChannelId  *uint
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// PostHit — найденный пост с рангом и фрагментом текста, где совпадения обрамлены HighlightStart и HighlightStop
+type PostHit struct {
+	PostId    uint
+	ChannelId uint
+	Title     string
+	Snippet   string
+	Rank      float64
+	CreatedAt time.Time
+}
+
+// ChannelHit — найденный канал с рангом и фрагментом описания
+type ChannelHit struct {
+	ChannelId uint
+	Name      string
+	Snippet   string
+	Rank      float64
+}
+
+// Маркеры подсветки в Snippet. Это управляющие символы, которых нет в пользовательском тексте,
+// поэтому вызывающий код может экранировать фрагмент и только затем заменить маркеры на разметку.
+const (
+	HighlightStart = "\x01"
+	HighlightStop  = "\x02"
+)
+
+// Engine — поисковый движок. Реализация по умолчанию работает на полнотекстовом поиске PostgreSQL.
+type Engine interface {
+	SearchPosts(ctx context.Context, query Query) ([]PostHit, error)
+	SearchChannels(ctx context.Context, query Query) ([]ChannelHit, error)
+}
+
+// NormalizeLanguage приводит язык пользователя к поддерживаемому, по умолчанию — русский
+func NormalizeLanguage(language string) string {
+	if language == LanguageEnglish {
+		return LanguageEnglish
+	}
+	return LanguageRussian
+}
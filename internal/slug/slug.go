@@ -0,0 +1,90 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxLength — максимальная длина слага вместе с числовым суффиксом
+const MaxLength = 80
+
+// cyrillic — транслитерация кириллицы в латиницу по упрощенной системе загранпаспортов
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Make строит слаг из произвольной строки: кириллица транслитерируется, буквы приводятся
+// к нижнему регистру, остальные символы заменяются дефисами. Для строки без букв и цифр
+// возвращается пустая строка.
+func Make(value string) string {
+	var builder strings.Builder
+	pendingDash := false
+
+	for _, r := range strings.ToLower(value) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		default:
+			if latin, ok := cyrillic[r]; ok {
+				part = latin
+			}
+		}
+
+		if part == "" {
+			// Твердый и мягкий знаки пропускаются, не разрывая слово
+			if r != 'ъ' && r != 'ь' {
+				pendingDash = builder.Len() > 0
+			}
+			continue
+		}
+
+		if pendingDash {
+			builder.WriteByte('-')
+			pendingDash = false
+		}
+		builder.WriteString(part)
+	}
+
+	return truncate(builder.String(), MaxLength)
+}
+
+// WithSuffix добавляет к слагу числовой суффикс, укорачивая основу, чтобы уложиться в MaxLength
+func WithSuffix(base string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return truncate(base, MaxLength-len(suffix)) + suffix
+}
+
+// Unique подбирает свободный слаг: base, base-2, base-3 и так далее. Функция taken сообщает,
+// занят ли кандидат.
+func Unique(base string, taken func(candidate string) (bool, error)) (string, error) {
+	candidate := base
+	for n := 2; ; n++ {
+		busy, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !busy {
+			return candidate, nil
+		}
+		candidate = WithSuffix(base, n)
+	}
+}
+
+// truncate обрезает слаг до limit байт по границе слова, если она есть
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	value = value[:limit]
+	if i := strings.LastIndexByte(value, '-'); i > 0 {
+		value = value[:i]
+	}
+	return strings.Trim(value, "-")
+}
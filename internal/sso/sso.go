@@ -0,0 +1,172 @@
+package sso
+
+import (
+	"blogpoint-backend/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"sync"
+	"time"
+)
+
+// discoveryTimeout ограничивает запрос к /.well-known/openid-configuration провайдера
+const discoveryTimeout = 10 * time.Second
+
+var (
+	ErrUnknownProvider = errors.New("unknown provider")
+	ErrNonceMismatch   = errors.New("id token nonce mismatch")
+)
+
+// Claims — данные пользователя из ID токена
+type Claims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Locale            string `json:"locale"`
+	Nonce             string `json:"nonce"`
+}
+
+// Provider — OIDC провайдер с авторизацией по коду и PKCE. Discovery выполняется при первом обращении,
+// поэтому недоступный провайдер не мешает запуску приложения, а неудачная попытка повторяется при следующем входе.
+type Provider struct {
+	cfg config.OIDCProviderConfig
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// DisplayName возвращает название для кнопки входа
+func (p *Provider) DisplayName() string {
+	if p.cfg.DisplayName != "" {
+		return p.cfg.DisplayName
+	}
+	return p.cfg.Name
+}
+
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc discovery for %s: %w", p.cfg.Name, err)
+	}
+
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientId,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientId})
+	return p.oauth, p.verifier, nil
+}
+
+// AuthCodeURL возвращает адрес страницы входа у провайдера. В запрос передается S256-хеш verifier (PKCE),
+// сам verifier нужен потом в Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauth, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange обменивает код авторизации на токены, проверяет подпись, аудиторию и nonce ID токена
+// и возвращает данные пользователя
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	oauth, idVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc code exchange: %w", err)
+	}
+
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, errors.New("oidc token response has no id_token")
+	}
+
+	idToken, err := idVerifier.Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc id token: %w", err)
+	}
+
+	var claims Claims
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("oidc id token claims: %w", err)
+	}
+	if claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return &claims, nil
+}
+
+// Registry — настроенные провайдеры по имени
+type Registry struct {
+	providers   map[string]*Provider
+	order       []*Provider
+	frontendURL string
+}
+
+func NewRegistry(cfg config.OIDCConfig) *Registry {
+	registry := &Registry{
+		providers:   make(map[string]*Provider, len(cfg.Providers)),
+		frontendURL: cfg.FrontendURL,
+	}
+	for _, providerCfg := range cfg.Providers {
+		provider := &Provider{cfg: providerCfg}
+		registry.providers[providerCfg.Name] = provider
+		registry.order = append(registry.order, provider)
+	}
+	return registry
+}
+
+func (r *Registry) Get(name string) (*Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// Providers возвращает провайдеров в порядке из конфигурации
+func (r *Registry) Providers() []*Provider {
+	return r.order
+}
+
+// FrontendURL — страница фронтенда, на которую возвращается браузер после входа у провайдера
+func (r *Registry) FrontendURL() string {
+	return r.frontendURL
+}
+
+// GenerateVerifier создает случайный code_verifier для PKCE
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
@@ -0,0 +1,230 @@
+package sso
+
+import (
+	"blogpoint-backend/internal/config"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testClientId = "blogpoint"
+	testKeyId    = "test-key"
+)
+
+// stubIssuer — минимальный OIDC провайдер: discovery, JWKS и token endpoint с проверкой PKCE
+type stubIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]stubGrant
+}
+
+// stubGrant — то, что провайдер запомнил при выдаче кода авторизации
+type stubGrant struct {
+	challenge string
+	nonce     string
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	issuer := &stubIssuer{t: t, key: key, codes: make(map[string]stubGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (s *stubIssuer) provider() *Provider {
+	return NewRegistry(config.OIDCConfig{
+		Providers: []config.OIDCProviderConfig{{
+			Name:         "stub",
+			Issuer:       s.server.URL,
+			ClientId:     testClientId,
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost/api/oidc/stub/callback",
+		}},
+	}).providers["stub"]
+}
+
+// authorize повторяет страницу входа провайдера: разбирает адрес из AuthCodeURL и выдает код
+func (s *stubIssuer) authorize(authURL string) string {
+	s.t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		s.t.Fatalf("parse auth url: %v", err)
+	}
+	query := parsed.Query()
+	if method := query.Get("code_challenge_method"); method != "S256" {
+		s.t.Fatalf("code_challenge_method = %q, want S256", method)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	code := "code-" + query.Get("state")
+	s.codes[code] = stubGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	return code
+}
+
+func (s *stubIssuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.server.URL,
+		"authorization_endpoint":                s.server.URL + "/authorize",
+		"token_endpoint":                        s.server.URL + "/token",
+		"jwks_uri":                              s.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (s *stubIssuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": testKeyId,
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *stubIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mu.Lock()
+	grant, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	hash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(hash[:]) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token": s.sign(map[string]interface{}{
+			"iss":            s.server.URL,
+			"aud":            testClientId,
+			"sub":            "user-1",
+			"email":          "user@example.com",
+			"email_verified": true,
+			"nonce":          grant.nonce,
+			"iat":            now.Unix(),
+			"exp":            now.Add(time.Hour).Unix(),
+		}),
+	})
+}
+
+// sign собирает ID токен, подписанный RS256
+func (s *stubIssuer) sign(claims map[string]interface{}) string {
+	s.t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": testKeyId})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		s.t.Fatalf("marshal claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		s.t.Fatalf("sign id token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestExchangeWithPKCE(t *testing.T) {
+	issuer := newStubIssuer(t)
+	provider := issuer.provider()
+	ctx := context.Background()
+
+	verifier := GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	claims, err := provider.Exchange(ctx, issuer.authorize(authURL), verifier, "nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "user@example.com" || !claims.EmailVerified {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	issuer := newStubIssuer(t)
+	provider := issuer.provider()
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", GenerateVerifier())
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	_, err = provider.Exchange(ctx, issuer.authorize(authURL), GenerateVerifier(), "nonce")
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("Exchange error = %v, want invalid_grant", err)
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	issuer := newStubIssuer(t)
+	provider := issuer.provider()
+	ctx := context.Background()
+
+	verifier := GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	_, err = provider.Exchange(ctx, issuer.authorize(authURL), verifier, "other-nonce")
+	if !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("Exchange error = %v, want ErrNonceMismatch", err)
+	}
+}
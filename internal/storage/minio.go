@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"blogpoint-backend/internal/config"
 	"context"
 	"errors"
 	"fmt"
@@ -9,26 +10,54 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"io"
 	"log"
-	"os"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var MinioClient *minio.Client
 
-const bucketName = "blogpoint-bucket"
+var (
+	bucketName     string
+	publicEndpoint string
+
+	// presignClient подписывает ссылки для адреса publicEndpoint: подпись включает хост,
+	// поэтому ссылка на внутренний адрес не открылась бы у клиента
+	presignClient *minio.Client
+)
+
+// PrivatePrefix — каталог закрытых файлов, они доступны только по подписанной ссылке или через API
+const PrivatePrefix = "private/"
+
+// publicPrefixes — типы верхнего уровня MIME, по которым раскладываются публичные файлы
+var publicPrefixes = []string{"application", "audio", "font", "image", "message", "model", "multipart", "text", "video"}
+
+// ErrPresignUnavailable — publicEndpoint не указывает напрямую на MinIO, подписанные ссылки не выдаются
+var ErrPresignUnavailable = errors.New("presigned urls are not available for the public endpoint")
 
 // InitMinio инициализирует MinIO клиент и создает бакет, если его нет
-func InitMinio() {
+func InitMinio(cfg config.MinioConfig) {
+	bucketName = cfg.Bucket
+	publicEndpoint = cfg.PublicEndpoint
+
 	var err error
 	MinioClient, err = minio.New(
-		os.Getenv("MINIO_INTERNAL_ENDPOINT"),
+		cfg.InternalEndpoint,
 		&minio.Options{
-			Creds:  credentials.NewStaticV4(os.Getenv("MINIO_ACCESS_KEY"), os.Getenv("MINIO_SECRET_KEY"), ""),
-			Secure: false, // false, если MinIO работает без SSL
+			Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+			Secure: cfg.UseSSL,
 		})
 	if err != nil {
 		log.Fatalf("MinIO initialization error: %v", err)
 	}
 
+	presignClient, err = newPresignClient(cfg)
+	if err != nil {
+		log.Printf("Загрузка по подписанным ссылкам недоступна: %v", err)
+	}
+
 	// Проверяем существование бакета
 	exists, err := MinioClient.BucketExists(context.Background(), bucketName)
 	if err != nil {
@@ -42,25 +71,144 @@ func InitMinio() {
 			log.Fatalf("Error creating bucket: %v", err)
 		}
 		fmt.Printf("Бакет %s создан\n", bucketName)
+	}
+
+	// Политика обновляется при каждом запуске, чтобы заменить прежнюю, открывавшую весь бакет
+	err = MinioClient.SetBucketPolicy(context.Background(), bucketName, publicReadPolicy())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("Public read access is set for bucket %s\n", bucketName)
+}
+
+// publicReadPolicy открывает чтение только каталогов публичных файлов. Объекты лежат в каталогах по типу MIME
+// (image/..., video/...), а закрытые файлы — в PrivatePrefix, который в политику не входит.
+func publicReadPolicy() string {
+	resources := make([]string, 0, len(publicPrefixes))
+	for _, prefix := range publicPrefixes {
+		resources = append(resources, `"arn:aws:s3:::`+bucketName+`/`+prefix+`/*"`)
+	}
 
-		policy := `{
+	return `{
 		"Version": "2012-10-17",
 		"Statement": [
 			{
 				"Effect": "Allow",
 				"Principal": "*",
 				"Action": "s3:GetObject",
-				"Resource": "arn:aws:s3:::` + bucketName + `/*"
+				"Resource": [` + strings.Join(resources, ", ") + `]
 			}
 		]
 	}`
+}
 
-		err = MinioClient.SetBucketPolicy(context.Background(), bucketName, policy)
-		if err != nil {
-			log.Fatalln(err)
+// newPresignClient создает клиент для публичного адреса. Запросов к хранилищу он не делает: регион задан заранее.
+func newPresignClient(cfg config.MinioConfig) (*minio.Client, error) {
+	endpoint, err := url.Parse(cfg.PublicEndpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("public endpoint %q is not an absolute http(s) url", cfg.PublicEndpoint)
+	}
+	if endpoint.Path != "" && endpoint.Path != "/" {
+		return nil, fmt.Errorf("public endpoint %q has a path prefix", cfg.PublicEndpoint)
+	}
+
+	return minio.New(endpoint.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: endpoint.Scheme == "https",
+		Region: cfg.Region,
+	})
+}
+
+// PresignUpload возвращает ссылку для загрузки объекта запросом PUT. Content-Type и Content-Length входят в подпись,
+// поэтому хранилище примет только файл заявленного типа и размера.
+func PresignUpload(ctx context.Context, filename string, size int64, mimeType string, expires time.Duration) (string, error) {
+	if presignClient == nil {
+		return "", ErrPresignUnavailable
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", mimeType)
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+
+	presigned, err := presignClient.PresignHeader(ctx, http.MethodPut, bucketName, filename, expires, nil, headers)
+	if err != nil {
+		return "", err
+	}
+	return presigned.String(), nil
+}
+
+// PresignDownload возвращает временную ссылку на закрытый объект
+func PresignDownload(filename string, expires time.Duration) (string, error) {
+	if presignClient == nil {
+		return "", ErrPresignUnavailable
+	}
+
+	presigned, err := presignClient.PresignedGetObject(context.Background(), bucketName, filename, expires, nil)
+	if err != nil {
+		return "", err
+	}
+	return presigned.String(), nil
+}
+
+// StatFromMinIO возвращает сведения об объекте; если объекта нет — ошибку 404
+func StatFromMinIO(ctx context.Context, filename string) (minio.ObjectInfo, error) {
+	info, err := MinioClient.StatObject(ctx, bucketName, filename, minio.StatObjectOptions{})
+	if err != nil {
+		var minioErr minio.ErrorResponse
+		if errors.As(err, &minioErr) && minioErr.Code == "NoSuchKey" {
+			return info, fiber.NewError(fiber.StatusNotFound, "File not found")
 		}
-		fmt.Printf("Bucket %s is set to public\n", bucketName)
+		return info, fiber.NewError(fiber.StatusInternalServerError, "Error checking file existence")
+	}
+	return info, nil
+}
+
+// OpenFromMinIO открывает объект на чтение с байта start по end включительно; при start < 0 — целиком
+func OpenFromMinIO(ctx context.Context, filename string, start, end int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if start >= 0 {
+		if err := opts.SetRange(start, end); err != nil {
+			return nil, fiber.NewError(fiber.StatusRequestedRangeNotSatisfiable, "Invalid range")
+		}
+	}
+
+	object, err := MinioClient.GetObject(ctx, bucketName, filename, opts)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error reading file")
+	}
+	return object, nil
+}
+
+// CopyInMinIO копирует объект внутри бакета
+func CopyInMinIO(ctx context.Context, src string, dst string) error {
+	_, err := MinioClient.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: bucketName, Object: dst},
+		minio.CopySrcOptions{Bucket: bucketName, Object: src},
+	)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Error copying file")
+	}
+	return nil
+}
+
+// ReadFromMinIO читает первые limit байт объекта (весь объект, если он короче)
+func ReadFromMinIO(ctx context.Context, filename string, limit int64) ([]byte, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(0, limit-1); err != nil {
+		return nil, err
+	}
+
+	object, err := MinioClient.GetObject(ctx, bucketName, filename, opts)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error reading file")
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, limit))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error reading file")
 	}
+	return data, nil
 }
 
 // UploadToMinIO загружает файл в MinIO
@@ -80,8 +228,79 @@ func UploadToMinIO(ctx context.Context, filename string, src io.Reader, fileSize
 	return nil
 }
 
-// DeleteFromMinIO удаляет файл из MinIO
-func DeleteFromMinIO(ctx context.Context, filename string) error {
+// StartMultipartUpload начинает составную загрузку объекта и возвращает ее идентификатор в MinIO
+func StartMultipartUpload(ctx context.Context, filename string, mimeType string) (string, error) {
+	core := minio.Core{Client: MinioClient}
+	uploadId, err := core.NewMultipartUpload(ctx, bucketName, filename, minio.PutObjectOptions{ContentType: mimeType})
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusInternalServerError, "Error starting upload")
+	}
+	return uploadId, nil
+}
+
+// UploadPart загружает часть составной загрузки. Повторная загрузка части с тем же номером заменяет ее.
+// Если md5Base64 не пустой, хранилище сверяет с ним содержимое части.
+func UploadPart(ctx context.Context, filename string, uploadId string, part int, src io.Reader, size int64, md5Base64 string) error {
+	core := minio.Core{Client: MinioClient}
+	_, err := core.PutObjectPart(ctx, bucketName, filename, uploadId, part, src, size, minio.PutObjectPartOptions{Md5Base64: md5Base64})
+	if err != nil {
+		var minioErr minio.ErrorResponse
+		if errors.As(err, &minioErr) && (minioErr.Code == "BadDigest" || minioErr.Code == "InvalidDigest") {
+			return fiber.NewError(fiber.StatusBadRequest, "Chunk checksum does not match")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Chunk upload error")
+	}
+	return nil
+}
+
+// ListUploadedParts возвращает все загруженные части составной загрузки по возрастанию номера
+func ListUploadedParts(ctx context.Context, filename string, uploadId string) ([]minio.ObjectPart, error) {
+	core := minio.Core{Client: MinioClient}
+
+	var parts []minio.ObjectPart
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(ctx, bucketName, filename, uploadId, marker, 1000)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Error listing uploaded chunks")
+		}
+		parts = append(parts, result.ObjectParts...)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// CompleteMultipartUpload собирает объект из загруженных частей
+func CompleteMultipartUpload(ctx context.Context, filename string, uploadId string, parts []minio.ObjectPart) error {
+	completed := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+
+	core := minio.Core{Client: MinioClient}
+	if _, err := core.CompleteMultipartUpload(ctx, bucketName, filename, uploadId, completed, minio.PutObjectOptions{}); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Error completing upload")
+	}
+	return nil
+}
+
+// AbortMultipartUpload отменяет составную загрузку и освобождает место, занятое ее частями
+func AbortMultipartUpload(ctx context.Context, filename string, uploadId string) error {
+	core := minio.Core{Client: MinioClient}
+	if err := core.AbortMultipartUpload(ctx, bucketName, filename, uploadId); err != nil {
+		var minioErr minio.ErrorResponse
+		if errors.As(err, &minioErr) && minioErr.Code == "NoSuchUpload" {
+			return nil
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Error aborting upload")
+	}
+	return nil
+}
+
+// DeleteFromMinIO удаляет файл из MinIO вместе с его вариантами (уменьшенными копиями изображения)
+func DeleteFromMinIO(ctx context.Context, filename string, variants ...string) error {
 	// Проверяем, существует ли файл в хранилище
 	_, err := MinioClient.StatObject(ctx, bucketName, filename, minio.StatObjectOptions{})
 	if err != nil {
@@ -95,9 +314,16 @@ func DeleteFromMinIO(ctx context.Context, filename string) error {
 	if err = MinioClient.RemoveObject(ctx, bucketName, filename, minio.RemoveObjectOptions{}); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete file from storage")
 	}
+
+	// Оставшийся вариант не мешает удалению файла, поэтому ошибку только пишем в лог
+	for _, variant := range variants {
+		if err = MinioClient.RemoveObject(ctx, bucketName, variant, minio.RemoveObjectOptions{}); err != nil {
+			log.Printf("Не удалось удалить вариант %s: %v", variant, err)
+		}
+	}
 	return nil
 }
 
 func GetUrl(filename string) string {
-	return os.Getenv("MINIO_PUBLIC_ENDPOINT") + "/" + bucketName + "/" + filename
+	return publicEndpoint + "/" + bucketName + "/" + filename
 }
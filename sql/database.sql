@@ -6,12 +6,15 @@ CREATE TABLE roles (
 
 INSERT INTO roles (name, description) VALUES
     ('user', 'Standard user role'),
-    ('moderator', 'Moderator role');
+    ('moderator', 'Moderator role'),
+    ('admin', 'Administrator role');
 
 CREATE TABLE files (
     id SERIAL PRIMARY KEY,
     filename varchar(100) UNIQUE NOT NULL,
     mime_type varchar(30) NOT NULL,
+    visibility VARCHAR(10) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'private')),
+    variants JSONB NOT NULL DEFAULT '{}',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -24,22 +27,87 @@ CREATE TABLE users (
     language VARCHAR(2) NOT NULL,
     is_verified BOOLEAN DEFAULT FALSE,
     logo_id INT REFERENCES files(id) ON DELETE SET NULL,
+    failed_logins INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    totp_secret VARCHAR(64),
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_last_step BIGINT NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 ALTER TABLE files ADD COLUMN owner_id INT;
 ALTER TABLE files ADD CONSTRAINT fk_files_user FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
 
+CREATE TABLE pending_uploads (
+    id SERIAL PRIMARY KEY,
+    owner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename VARCHAR(100) UNIQUE NOT NULL,
+    size BIGINT NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    type VARCHAR(30) NOT NULL DEFAULT '',
+    multipart_id VARCHAR(255),
+    chunk_size BIGINT NOT NULL DEFAULT 0,
+    visibility VARCHAR(10) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'private')),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX pending_uploads_owner_id_idx ON pending_uploads(owner_id);
+
 CREATE TABLE verification_codes (
     id SERIAL PRIMARY KEY,
     user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    code VARCHAR(6) NOT NULL,
+    code VARCHAR(64) NOT NULL,
     type TEXT CHECK (type IN ('email_verification', 'account_deletion', 'password_reset')),
     expires_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE (user_id, type)
 );
 
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash VARCHAR(64) NOT NULL,
+    user_agent TEXT DEFAULT '',
+    ip VARCHAR(45) DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions(user_id);
+
+CREATE TABLE used_refresh_tokens (
+    session_id INT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, token_hash)
+);
+
+CREATE TABLE recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX recovery_codes_user_id_idx ON recovery_codes(user_id);
+
+CREATE TABLE identities (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX identities_user_id_idx ON identities(user_id);
+
 CREATE TABLE categories (
     id SERIAL PRIMARY KEY,
     name VARCHAR(100) UNIQUE NOT NULL,
@@ -55,11 +123,29 @@ CREATE TABLE tags (
 CREATE TABLE channels (
     id SERIAL PRIMARY KEY,
     name VARCHAR(100) UNIQUE NOT NULL,
+    slug VARCHAR(100) UNIQUE,
     description TEXT DEFAULT '',
     category_id INT REFERENCES categories(id) ON DELETE SET NULL,
     owner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     subs_count INT NOT NULL DEFAULT 0 CHECK (subs_count >= 0),
     logo_id INT REFERENCES files(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_ru tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+    ) STORED,
+    search_en tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED
+);
+
+CREATE INDEX channels_search_ru_idx ON channels USING GIN (search_ru);
+CREATE INDEX channels_search_en_idx ON channels USING GIN (search_en);
+
+CREATE TABLE channel_slug_redirects (
+    slug VARCHAR(100) PRIMARY KEY,
+    channel_id INT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -93,13 +179,53 @@ CREATE TABLE posts (
     channel_id INT REFERENCES channels(id) ON DELETE CASCADE,
     preview_image_id INT REFERENCES files(id) ON DELETE SET NULL,
     title VARCHAR(200) NOT NULL,
+    slug VARCHAR(100),
     content TEXT NOT NULL,
+    content_format VARCHAR(20) NOT NULL DEFAULT 'plain' CHECK (content_format IN ('plain', 'markdown')),
     likes_count INT DEFAULT 0,
     dislikes_count INT DEFAULT 0,
     views_count INT DEFAULT 0,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    is_hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived')),
+    publish_at TIMESTAMP,
+    published_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    search_ru tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(content, '')), 'B')
+    ) STORED,
+    search_en tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'B')
+    ) STORED,
+    CONSTRAINT posts_published_at_check CHECK (status <> 'published' OR published_at IS NOT NULL)
 );
 
+CREATE UNIQUE INDEX posts_channel_id_slug_idx ON posts(channel_id, slug);
+CREATE INDEX posts_channel_id_published_at_idx ON posts(channel_id, published_at DESC) WHERE status = 'published';
+CREATE INDEX posts_publish_at_idx ON posts(publish_at) WHERE status = 'scheduled';
+CREATE INDEX posts_search_ru_idx ON posts USING GIN (search_ru);
+CREATE INDEX posts_search_en_idx ON posts USING GIN (search_en);
+
+CREATE TABLE post_slug_redirects (
+    channel_id INT REFERENCES channels(id) ON DELETE CASCADE,
+    slug VARCHAR(100),
+    post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, slug)
+);
+
+CREATE TABLE post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+    author_id INT REFERENCES users(id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX post_revisions_post_id_idx ON post_revisions(post_id, created_at DESC);
+
 CREATE TABLE post_images (
     post_id INT REFERENCES posts(id) ON DELETE CASCADE,
     file_id INT REFERENCES files(id) ON DELETE CASCADE,
@@ -127,17 +253,38 @@ CREATE TABLE post_reactions (
     CONSTRAINT unique_reaction_per_user UNIQUE (user_id, post_id)
 );
 
+CREATE TABLE seen_posts (
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+    seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id)
+);
+
 CREATE TABLE comments (
     id SERIAL PRIMARY KEY,
     post_id INT REFERENCES posts(id) ON DELETE CASCADE,
     user_id INT REFERENCES users(id) ON DELETE CASCADE,
     content TEXT NOT NULL,
     is_deleted BOOLEAN DEFAULT FALSE,
+    is_hidden BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 ALTER TABLE comments ADD COLUMN parent_id INT REFERENCES comments(id) ON DELETE CASCADE;
 
+CREATE TABLE moderation_logs (
+    id SERIAL PRIMARY KEY,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    channel_id INT REFERENCES channels(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INT NOT NULL,
+    details TEXT DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX moderation_logs_channel_id_idx ON moderation_logs(channel_id, created_at DESC);
+
 CREATE TYPE target AS ENUM ('channel', 'post', 'comment');
 CREATE TYPE status AS ENUM ('open', 'in progress', 'close');
 
@@ -149,9 +296,56 @@ CREATE TABLE complaints (
     complaint_type VARCHAR(50) NOT NULL,
     description TEXT NOT NULL,
     status status DEFAULT 'open',
+    assignee_id INT REFERENCES users(id) ON DELETE SET NULL,
+    resolution TEXT DEFAULT '',
+    action VARCHAR(20) DEFAULT '',
+    resolved_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE INDEX complaints_status_idx ON complaints(status, created_at);
+CREATE INDEX complaints_target_idx ON complaints(target_type, target_id);
+
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('comment_reply', 'new_subscriber', 'new_post')),
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    channel_id INT REFERENCES channels(id) ON DELETE CASCADE,
+    post_id INT REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INT REFERENCES comments(id) ON DELETE CASCADE,
+    data JSONB NOT NULL DEFAULT '{}',
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX notifications_user_id_idx ON notifications(user_id, created_at DESC);
+CREATE INDEX notifications_unread_idx ON notifications(user_id) WHERE NOT is_read;
+
+CREATE TABLE outbox_emails (
+    id SERIAL PRIMARY KEY,
+    recipients JSONB NOT NULL,
+    subject TEXT NOT NULL,
+    html TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+CREATE INDEX outbox_emails_pending_idx ON outbox_emails(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE notification_preferences (
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('comment_reply', 'new_subscriber', 'new_post')),
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    email BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (user_id, type)
+);
+
 
 CREATE OR REPLACE FUNCTION update_likes_dislikes() RETURNS TRIGGER AS $$
 BEGIN
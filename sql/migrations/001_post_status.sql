@@ -0,0 +1,20 @@
+-- Перевод существующей базы на статусы постов: колонки статуса и времени публикации, индексы
+-- и время публикации уже опубликованных постов, взятое из времени создания.
+-- Новая база создается из database.sql и в этом скрипте не нуждается.
+BEGIN;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'scheduled', 'published', 'archived'));
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+
+UPDATE posts SET published_at = COALESCE(created_at, CURRENT_TIMESTAMP)
+WHERE status = 'published' AND published_at IS NULL;
+
+ALTER TABLE posts ADD CONSTRAINT posts_published_at_check CHECK (status <> 'published' OR published_at IS NOT NULL);
+
+DROP INDEX IF EXISTS posts_channel_id_created_at_idx;
+CREATE INDEX IF NOT EXISTS posts_channel_id_published_at_idx ON posts(channel_id, published_at DESC) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS posts_publish_at_idx ON posts(publish_at) WHERE status = 'scheduled';
+
+COMMIT;
@@ -1,11 +1,16 @@
 package utils
 
 import (
+	"context"
 	"log"
 	"time"
 
+	"blogpoint-backend/internal/controllers"
 	"blogpoint-backend/internal/models"
+	"blogpoint-backend/internal/notify"
 	"blogpoint-backend/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func StartCleanupTask() {
@@ -25,6 +30,26 @@ func StartCleanupTask() {
 				Where("is_verified = ? AND created_at < ?", false, time.Now().Add(-24*time.Hour)).
 				Delete(&models.User{})
 			log.Printf("🧹 Удалено %d неподтверждённых аккаунтов", userResult.RowsAffected)
+
+			// Удаляем истекшие и давно отозванные сессии
+			sessionResult := repository.DB.
+				Where("expires_at < ? OR revoked_at < ?", time.Now(), time.Now().Add(-7*24*time.Hour)).
+				Delete(&models.Session{})
+			log.Printf("🧹 Удалено %d устаревших сессий", sessionResult.RowsAffected)
+
+			// Удаляем отправленные письма из очереди, неотправленные оставляем для разбора
+			outboxResult := repository.DB.
+				Where("status = ? AND sent_at < ?", models.OutboxStatusSent, time.Now().Add(-7*24*time.Hour)).
+				Delete(&models.OutboxEmail{})
+			log.Printf("🧹 Удалено %d отправленных писем из очереди", outboxResult.RowsAffected)
+
+			// Удаляем незавершенные в срок загрузки вместе с объектами в хранилище
+			uploadCount, err := controllers.DiscardExpiredUploads(context.Background())
+			if err != nil {
+				log.Printf("❌ Ошибка при удалении незавершенных загрузок: %v", err)
+			} else {
+				log.Printf("🧹 Удалено %d незавершенных загрузок", uploadCount)
+			}
 		}
 	}()
 }
@@ -49,9 +74,10 @@ func StartStatisticsTask() {
 					Date:      today,
 				}
 
+				// Черновики, отложенные и архивные посты в статистику канала не входят
 				repository.DB.
 					Model(&models.Post{}).
-					Where("channel_id = ?", ch.Id).
+					Where("channel_id = ? AND status = ?", ch.Id, models.PostStatusPublished).
 					Select("COALESCE(SUM(likes_count), 0), COALESCE(SUM(dislikes_count), 0), COALESCE(SUM(views_count), 0), COUNT(*)").
 					Row().
 					Scan(&stats.Likes, &stats.Dislikes, &stats.Views, &stats.Posts)
@@ -60,7 +86,7 @@ func StartStatisticsTask() {
 				repository.DB.
 					Model(&models.Comment{}).
 					Joins("JOIN posts ON comments.post_id = posts.id").
-					Where("posts.channel_id = ?", ch.Id).
+					Where("posts.channel_id = ? AND posts.status = ?", ch.Id, models.PostStatusPublished).
 					Count(&commentsCount)
 
 				stats.Comments = int(commentsCount)
@@ -73,3 +99,54 @@ func StartStatisticsTask() {
 		}
 	}()
 }
+
+// StartPublishingTask раз в минуту публикует отложенные посты, время публикации которых наступило,
+// и уведомляет о них подписчиков каналов.
+// Временем публикации становится запланированное, а не фактическое, чтобы пост встал в ленту на свое место.
+func StartPublishingTask(notifier *notify.Dispatcher) {
+	go func() {
+		for {
+			var posts []models.Post
+			result := repository.DB.
+				Model(&posts).
+				Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "channel_id"}, {Name: "title"}}}).
+				Where("status = ? AND publish_at <= ?", models.PostStatusScheduled, time.Now()).
+				Updates(map[string]interface{}{
+					"status":       models.PostStatusPublished,
+					"published_at": gorm.Expr("publish_at"),
+				})
+			if result.Error != nil {
+				log.Printf("❌ Ошибка при публикации отложенных постов: %v", result.Error)
+			} else if result.RowsAffected > 0 {
+				log.Printf("📰 Опубликовано %d отложенных постов", result.RowsAffected)
+				notifyPublished(notifier, posts)
+			}
+
+			time.Sleep(time.Minute)
+		}
+	}()
+}
+
+func notifyPublished(notifier *notify.Dispatcher, posts []models.Post) {
+	channelIds := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		channelIds = append(channelIds, post.ChannelId)
+	}
+
+	var channels []models.Channel
+	if err := repository.DB.Where("id IN ?", channelIds).Find(&channels).Error; err != nil {
+		log.Printf("❌ Ошибка при получении каналов опубликованных постов: %v", err)
+		return
+	}
+
+	channelsById := make(map[uint]models.Channel, len(channels))
+	for _, channel := range channels {
+		channelsById[channel.Id] = channel
+	}
+
+	for _, post := range posts {
+		if channel, ok := channelsById[post.ChannelId]; ok {
+			notifier.PostPublished(post, channel)
+		}
+	}
+}